@@ -0,0 +1,4 @@
+data "mastodon_account" "example" {
+  acct    = "user@remote.example"
+  resolve = true
+}
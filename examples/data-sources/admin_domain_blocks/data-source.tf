@@ -0,0 +1,8 @@
+data "mastodon_admin_domain_blocks" "suspended" {
+  severity = "suspend"
+}
+
+# Generate import commands for existing blocks
+output "imports" {
+  value = [for block in data.mastodon_admin_domain_blocks.suspended.blocks : "terraform import 'mastodon_domain_block.this[\"${block.domain}\"]' ${block.domain}"]
+}
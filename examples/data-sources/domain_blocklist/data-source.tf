@@ -0,0 +1,15 @@
+data "mastodon_domain_blocklist" "shared" {
+  file = "${path.module}/blocklist.csv"
+}
+
+resource "mastodon_domain_block" "shared" {
+  for_each = data.mastodon_domain_blocklist.shared.blocks
+
+  domain          = each.key
+  severity        = each.value.severity
+  reject_media    = each.value.reject_media
+  reject_reports  = each.value.reject_reports
+  public_comment  = each.value.public_comment
+  private_comment = each.value.private_comment
+  obfuscate       = each.value.obfuscate
+}
@@ -0,0 +1,2 @@
+# Account preferences are imported using the id of the account the provider's access token belongs to
+terraform import mastodon_account_preferences.example 109371872356817513
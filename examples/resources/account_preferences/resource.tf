@@ -0,0 +1,12 @@
+variable "bot_access_token" {
+  type      = string
+  sensitive = true
+}
+
+resource "mastodon_account_preferences" "bot" {
+  access_token = var.bot_access_token
+
+  privacy   = "unlisted"
+  language  = "en"
+  sensitive = true
+}
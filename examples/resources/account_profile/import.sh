@@ -0,0 +1,2 @@
+# Account profiles are imported using the id of the account the access token belongs to
+terraform import mastodon_account_profile.example 109371872356817513
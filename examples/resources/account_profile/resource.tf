@@ -0,0 +1,11 @@
+resource "mastodon_account_profile" "example" {
+  display_name = "Server Announcements"
+  note         = "Announcements about this server. Not monitored, contact the moderators instead."
+  bot          = true
+  avatar       = "${path.module}/avatar.png"
+
+  fields = [
+    { name = "Contact", value = "admin@mastodon.example" },
+    { name = "Status", value = "https://status.mastodon.example" },
+  ]
+}
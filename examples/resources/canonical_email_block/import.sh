@@ -0,0 +1,2 @@
+# Canonical email blocks are imported using their id
+terraform import mastodon_canonical_email_block.example 123
@@ -0,0 +1,8 @@
+resource "mastodon_canonical_email_block" "example" {
+  canonical_email_hash = "ac9833382c566ac5d0c0c9e6aa21c97d8e594c6aac4cf4d0d5d84f97b13241b0"
+}
+
+# the address is hashed by the provider, but kept in state
+resource "mastodon_canonical_email_block" "by_email" {
+  email = "e.vader+spam@example.com"
+}
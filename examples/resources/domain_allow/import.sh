@@ -0,0 +1,2 @@
+# Domain allows are imported using the allowed domain
+terraform import mastodon_domain_allow.example friends.example
@@ -0,0 +1,3 @@
+resource "mastodon_domain_allow" "example" {
+  domain = "friends.example"
+}
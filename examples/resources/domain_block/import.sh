@@ -0,0 +1,2 @@
+# Domain blocks are imported using the blocked domain
+terraform import mastodon_domain_block.example spam.example
@@ -0,0 +1,7 @@
+resource "mastodon_domain_block" "example" {
+  domain         = "spam.example"
+  severity       = "suspend"
+  reject_media   = true
+  reject_reports = true
+  public_comment = "Spam"
+}
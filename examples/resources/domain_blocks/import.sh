@@ -0,0 +1,2 @@
+# Domain blocks are imported using the instance's domain
+terraform import mastodon_domain_blocks.example mastodon.example
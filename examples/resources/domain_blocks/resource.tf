@@ -0,0 +1,8 @@
+data "mastodon_domain_blocklist" "shared" {
+  file = "${path.module}/blocklist.csv"
+}
+
+resource "mastodon_domain_blocks" "example" {
+  authoritative = true
+  blocks        = values(data.mastodon_domain_blocklist.shared.blocks)
+}
@@ -0,0 +1,2 @@
+# Email domain blocks are imported using the blocked domain
+terraform import mastodon_email_domain_block.example disposable.example
@@ -0,0 +1,3 @@
+resource "mastodon_email_domain_block" "example" {
+  domain = "disposable.example"
+}
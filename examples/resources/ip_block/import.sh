@@ -0,0 +1,2 @@
+# IP blocks are imported using the blocked address or range, or the block's id
+terraform import mastodon_ip_block.example 192.0.2.0/24
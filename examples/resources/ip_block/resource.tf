@@ -0,0 +1,6 @@
+resource "mastodon_ip_block" "example" {
+  ip         = "192.0.2.0/24"
+  severity   = "sign_up_block"
+  comment    = "Sign up spam"
+  expires_in = 2592000
+}
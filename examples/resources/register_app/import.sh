@@ -0,0 +1,5 @@
+# Apps are imported using their client credentials
+terraform import mastodon_register_app.example client_id:client_secret
+
+# Servers that don't report the app's id, such as Mastodon before 4.3, need it included to delete the app on destroy
+terraform import mastodon_register_app.example id:client_id:client_secret
@@ -0,0 +1,2 @@
+# Statuses are imported using their id, they must have been posted by the account of the provider's access token
+terraform import mastodon_status.example 109372843234985734
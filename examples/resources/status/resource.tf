@@ -0,0 +1,16 @@
+resource "mastodon_status" "about" {
+  text       = <<-EOT
+    This account posts announcements about mastodon.example.
+    It's not monitored, contact the moderators instead.
+  EOT
+  visibility = "unlisted"
+  language   = "en"
+  pinned     = true
+}
+
+resource "mastodon_status" "rules" {
+  text           = file("${path.module}/rules.txt")
+  spoiler_text   = "Server rules"
+  in_reply_to_id = mastodon_status.about.id
+  pinned         = true
+}
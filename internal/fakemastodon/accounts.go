@@ -0,0 +1,439 @@
+package fakemastodon
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AddAccount creates a local account with username.
+func (s *Server) AddAccount(username string) *Account {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	account := &Account{
+		ID:           s.newID(),
+		Username:     username,
+		Acct:         username,
+		DisplayName:  username,
+		CreatedAt:    time.Now().UTC().Truncate(24 * time.Hour),
+		URL:          s.URL + "/@" + username,
+		Avatar:       s.URL + "/avatars/original/missing.png",
+		AvatarStatic: s.URL + "/avatars/original/missing.png",
+		Header:       s.URL + "/headers/original/missing.png",
+		HeaderStatic: s.URL + "/headers/original/missing.png",
+		Emojis:       []Emoji{},
+		Fields:       []Field{},
+		Roles:        []Role{},
+		Source: AccountSource{
+			Privacy: "public",
+			Fields:  []Field{},
+		},
+	}
+	s.accounts[account.ID] = account
+
+	return account
+}
+
+// AddRemoteAccount creates an account on another server, which the server only knows about once it's
+// been resolved through search.
+func (s *Server) AddRemoteAccount(username, domain string) *Account {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	account := &Account{
+		Username:     username,
+		Acct:         username + "@" + domain,
+		DisplayName:  username,
+		CreatedAt:    time.Now().UTC().Truncate(24 * time.Hour),
+		URL:          "https://" + domain + "/@" + username,
+		Avatar:       s.URL + "/avatars/original/missing.png",
+		AvatarStatic: s.URL + "/avatars/original/missing.png",
+		Header:       s.URL + "/headers/original/missing.png",
+		HeaderStatic: s.URL + "/headers/original/missing.png",
+		Emojis:       []Emoji{},
+		Fields:       []Field{},
+		Roles:        []Role{},
+	}
+	s.remoteAccounts[strings.ToLower(account.Acct)] = account
+
+	return account
+}
+
+// Account returns a copy of the account with id, or nil if it doesn't exist.
+func (s *Server) Account(id string) *Account {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	account, ok := s.accounts[id]
+	if !ok {
+		return nil
+	}
+	copied := *account
+
+	return &copied
+}
+
+// UpdateAccount calls f with the account with id so tests can simulate changes made outside the provider.
+func (s *Server) UpdateAccount(id string, f func(account *Account)) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if account, ok := s.accounts[id]; ok {
+		f(account)
+	}
+}
+
+func (s *Server) handleAccounts(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/api/v1/accounts/")
+
+	switch {
+	case id == "verify_credentials" && r.Method == http.MethodGet:
+		s.handleAccountVerifyCredentials(w, r)
+	case id == "update_credentials" && r.Method == http.MethodPatch:
+		s.handleAccountUpdateCredentials(w, r)
+	case id == "lookup" && r.Method == http.MethodGet:
+		s.handleAccountLookup(w, r)
+	case !strings.Contains(id, "/") && r.Method == http.MethodGet:
+		s.handleAccount(w, r, id)
+	default:
+		writeError(w, http.StatusNotFound, "Record not found")
+	}
+}
+
+func (s *Server) handleAccount(w http.ResponseWriter, r *http.Request, id string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	account, ok := s.accounts[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Record not found")
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, account)
+}
+
+func (s *Server) handleAccountLookup(w http.ResponseWriter, r *http.Request) {
+	acct := strings.TrimPrefix(r.URL.Query().Get("acct"), "@")
+	acct = strings.TrimSuffix(acct, "@"+s.Domain())
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, account := range s.accounts {
+		if strings.EqualFold(account.Acct, acct) {
+			writeJSON(w, http.StatusOK, account)
+
+			return
+		}
+	}
+
+	writeError(w, http.StatusNotFound, "Record not found")
+}
+
+func (s *Server) handleAccountVerifyCredentials(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	_, account, ok := s.authenticateUser(w, r, "read:accounts")
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, credentialAccount(account))
+}
+
+// credentialAccount returns account as returned to itself, with its source.
+func credentialAccount(account *Account) interface{} {
+	source := account.Source
+	source.Discoverable = account.Discoverable
+	source.HideCollections = account.HideCollections
+	source.Indexable = account.Indexable
+
+	return struct {
+		*Account
+		Source AccountSource `json:"source"`
+	}{account, source}
+}
+
+func (s *Server) handleAccountUpdateCredentials(w http.ResponseWriter, r *http.Request) {
+	values, err := params(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	_, account, ok := s.authenticateUser(w, r, "write:accounts")
+	if !ok {
+		return
+	}
+
+	updated := *account
+	if v, ok := values["display_name"]; ok {
+		if len([]rune(v[0])) > 30 {
+			writeError(w, http.StatusUnprocessableEntity, "Validation failed: Display name is too long (maximum is 30 characters)")
+
+			return
+		}
+		updated.DisplayName = v[0]
+	}
+	if v, ok := values["note"]; ok {
+		if len([]rune(v[0])) > 500 {
+			writeError(w, http.StatusUnprocessableEntity, "Validation failed: Note is too long (maximum is 500 characters)")
+
+			return
+		}
+		updated.Source.Note = v[0]
+		updated.Note = formatText(v[0])
+	}
+	updated.Locked = boolParam(values, "locked", updated.Locked)
+	updated.Bot = boolParam(values, "bot", updated.Bot)
+	updated.Discoverable = boolParam(values, "discoverable", updated.Discoverable)
+	updated.Indexable = boolParam(values, "indexable", updated.Indexable)
+	updated.HideCollections = boolParam(values, "hide_collections", updated.HideCollections)
+
+	// posting defaults
+	if v, ok := values["source[privacy]"]; ok {
+		if v[0] != "public" && v[0] != "unlisted" && v[0] != "private" {
+			writeError(w, http.StatusUnprocessableEntity, "Validation failed: Privacy is not included in the list")
+
+			return
+		}
+		updated.Source.Privacy = v[0]
+	}
+	updated.Source.Sensitive = boolParam(values, "source[sensitive]", updated.Source.Sensitive)
+	if v, ok := values["source[language]"]; ok {
+		switch language := v[0]; {
+		case language == "":
+			updated.Source.Language = nil
+		case !isLanguage(language):
+			writeError(w, http.StatusUnprocessableEntity, "Validation failed: Language is not included in the list")
+
+			return
+		default:
+			updated.Source.Language = &language
+		}
+	}
+
+	// fields are sent by index, fields with a blank name and value are removed
+	if fields, ok := fieldsParam(values); ok {
+		maxFields := s.Instance.Configuration.Accounts.MaxProfileFields
+		if maxFields == 0 {
+			maxFields = 4
+		}
+		if len(fields) > maxFields {
+			writeError(w, http.StatusUnprocessableEntity, "Validation failed: Fields is too long")
+
+			return
+		}
+
+		updated.Source.Fields = fields
+		updated.Fields = make([]Field, len(fields))
+		for i, field := range fields {
+			updated.Fields[i] = Field{Name: field.Name, Value: formatText(field.Value)}
+		}
+	}
+
+	for _, key := range []string{"avatar", "header"} {
+		image, err := s.imageParam(r, account, key)
+		if err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err.Error())
+
+			return
+		}
+		if image == "" {
+			continue
+		}
+
+		if key == "avatar" {
+			updated.Avatar, updated.AvatarStatic = image, image
+		} else {
+			updated.Header, updated.HeaderStatic = image, image
+		}
+	}
+
+	*account = updated
+
+	writeJSON(w, http.StatusOK, credentialAccount(account))
+}
+
+// isLanguage returns true if code looks like an ISO 639 language code, which is what Mastodon accepts.
+func isLanguage(code string) bool {
+	if len(code) != 2 && len(code) != 3 {
+		return false
+	}
+
+	for _, c := range code {
+		if c < 'a' || c > 'z' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// handlePreferences returns the posting and reading preferences of the authenticated user.
+func (s *Server) handlePreferences(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeMethodNotAllowed(w)
+
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	_, account, ok := s.authenticateUser(w, r, "read:accounts")
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"posting:default:visibility": account.Source.Privacy,
+		"posting:default:sensitive":  account.Source.Sensitive,
+		"posting:default:language":   account.Source.Language,
+		"reading:expand:media":       "default",
+		"reading:expand:spoilers":    false,
+	})
+}
+
+// imageParam stores the image uploaded as key and returns its URL, which is new for every upload. It
+// returns "" if no image was uploaded.
+func (s *Server) imageParam(r *http.Request, account *Account, key string) (string, error) {
+	if r.MultipartForm == nil || len(r.MultipartForm.File[key]) == 0 {
+		return "", nil
+	}
+	header := r.MultipartForm.File[key][0]
+
+	limits := s.Instance.Configuration.MediaAttachments
+	if header.Size > limits.ImageSizeLimit {
+		return "", fmt.Errorf("Validation failed: %s must be less than %d bytes", key, limits.ImageSizeLimit)
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	// like Mastodon, trust the content rather than the declared type
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(file, head)
+	contentType := http.DetectContentType(head[:n])
+
+	supported := false
+	for _, mimeType := range limits.SupportedMimeTypes {
+		if mimeType == contentType && strings.HasPrefix(mimeType, "image/") {
+			supported = true
+		}
+	}
+	if !supported {
+		return "", fmt.Errorf("Validation failed: %s has contents that are not what they are reported to be", key)
+	}
+
+	return fmt.Sprintf("%s/system/accounts/%ss/%s/original/%s.%s", s.URL, key, account.ID, s.newID(), strings.TrimPrefix(contentType, "image/")), nil
+}
+
+// fieldsParam returns the profile fields sent as fields_attributes[i][name] and fields_attributes[i][value],
+// and whether any were sent.
+func fieldsParam(values url.Values) ([]Field, bool) {
+	byIndex := map[int]*Field{}
+	for key, v := range values {
+		var i int
+		var attr string
+		if _, err := fmt.Sscanf(strings.NewReplacer("[", " ", "]", " ").Replace(key), "fields_attributes %d %s", &i, &attr); err != nil {
+			continue
+		}
+
+		if byIndex[i] == nil {
+			byIndex[i] = &Field{}
+		}
+		switch attr {
+		case "name":
+			byIndex[i].Name = v[0]
+		case "value":
+			byIndex[i].Value = v[0]
+		}
+	}
+
+	if len(byIndex) == 0 {
+		return nil, false
+	}
+
+	indexes := make([]int, 0, len(byIndex))
+	for i := range byIndex {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+
+	fields := []Field{}
+	for _, i := range indexes {
+		if field := byIndex[i]; field.Name != "" || field.Value != "" {
+			fields = append(fields, *field)
+		}
+	}
+
+	return fields, true
+}
+
+// formatText converts plain text to HTML the way Mastodon does for profiles, without linking anything.
+func formatText(text string) string {
+	if text == "" {
+		return ""
+	}
+
+	return "<p>" + strings.ReplaceAll(html.EscapeString(text), "\n", "<br />") + "</p>"
+}
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeMethodNotAllowed(w)
+
+		return
+	}
+
+	query := r.URL.Query()
+	q := strings.TrimPrefix(strings.TrimSpace(query.Get("q")), "@")
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	// resolving remote accounts requires a user, like Mastodon
+	if query.Get("resolve") == "true" {
+		if _, _, ok := s.authenticateUser(w, r, "read:search"); !ok {
+			return
+		}
+
+		if account, ok := s.remoteAccounts[strings.ToLower(q)]; ok {
+			account.ID = s.newID()
+			s.accounts[account.ID] = account
+			delete(s.remoteAccounts, strings.ToLower(q))
+		}
+	}
+
+	accounts := []*Account{}
+	if t := query.Get("type"); t == "" || t == "accounts" {
+		for _, account := range s.accounts {
+			if strings.Contains(strings.ToLower(account.Acct), strings.ToLower(q)) {
+				accounts = append(accounts, account)
+			}
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"accounts": accounts,
+		"statuses": []interface{}{},
+		"hashtags": []interface{}{},
+	})
+}
@@ -0,0 +1,146 @@
+package fakemastodon
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// CanonicalEmailHash returns the hash Mastodon blocks email by, ignoring case, dots and anything after a
+// plus in the local part.
+func CanonicalEmailHash(email string) string {
+	local, domain, _ := strings.Cut(strings.ToLower(email), "@")
+	local, _, _ = strings.Cut(strings.ReplaceAll(local, ".", ""), "+")
+
+	sum := sha256.Sum256([]byte(local + "@" + domain))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// CanonicalEmailBlock returns a copy of the block with hash, or nil if there is none.
+func (s *Server) CanonicalEmailBlock(hash string) *CanonicalEmailBlock {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, block := range s.canonicalEmailBlocks {
+		if block.CanonicalEmailHash == hash {
+			copied := *block
+
+			return &copied
+		}
+	}
+
+	return nil
+}
+
+// RemoveCanonicalEmailBlock deletes the block with hash.
+func (s *Server) RemoveCanonicalEmailBlock(hash string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for id, block := range s.canonicalEmailBlocks {
+		if block.CanonicalEmailHash == hash {
+			delete(s.canonicalEmailBlocks, id)
+		}
+	}
+}
+
+func (s *Server) handleAdminCanonicalEmailBlocks(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		if _, _, ok := s.authenticateUser(w, r, "admin:read:canonical_email_blocks"); !ok {
+			return
+		}
+
+		ids := make([]string, 0, len(s.canonicalEmailBlocks))
+		for id := range s.canonicalEmailBlocks {
+			ids = append(ids, id)
+		}
+
+		blocks := []*CanonicalEmailBlock{}
+		for _, id := range paginate(w, r, ids, 100, 200) {
+			blocks = append(blocks, s.canonicalEmailBlocks[id])
+		}
+
+		writeJSON(w, http.StatusOK, blocks)
+	case http.MethodPost:
+		values, err := params(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+
+			return
+		}
+
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		if _, _, ok := s.authenticateUser(w, r, "admin:write:canonical_email_blocks"); !ok {
+			return
+		}
+
+		hash := values.Get("canonical_email_hash")
+		if email := values.Get("email"); email != "" {
+			hash = CanonicalEmailHash(email)
+		}
+		if hash == "" {
+			writeError(w, http.StatusUnprocessableEntity, "Validation failed: Canonical email hash can't be blank")
+
+			return
+		}
+		for _, existing := range s.canonicalEmailBlocks {
+			if existing.CanonicalEmailHash == hash {
+				writeError(w, http.StatusUnprocessableEntity, "Validation failed: Canonical email hash has already been taken")
+
+				return
+			}
+		}
+
+		block := &CanonicalEmailBlock{
+			ID:                 s.newID(),
+			CanonicalEmailHash: hash,
+		}
+		s.canonicalEmailBlocks[block.ID] = block
+
+		writeJSON(w, http.StatusOK, block)
+	default:
+		writeMethodNotAllowed(w)
+	}
+}
+
+func (s *Server) handleAdminCanonicalEmailBlock(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/api/v1/admin/canonical_email_blocks/")
+
+	scope := "admin:write:canonical_email_blocks"
+	if r.Method == http.MethodGet {
+		scope = "admin:read:canonical_email_blocks"
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, _, ok := s.authenticateUser(w, r, scope); !ok {
+		return
+	}
+
+	block, ok := s.canonicalEmailBlocks[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Record not found")
+
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, block)
+	case http.MethodDelete:
+		delete(s.canonicalEmailBlocks, id)
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{})
+	default:
+		writeMethodNotAllowed(w)
+	}
+}
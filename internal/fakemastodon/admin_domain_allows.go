@@ -0,0 +1,149 @@
+package fakemastodon
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// AddDomainAllow creates a domain allow, returning a copy of it.
+func (s *Server) AddDomainAllow(domain string) *DomainAllow {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	allow := &DomainAllow{
+		ID:        s.newID(),
+		Domain:    domain,
+		CreatedAt: time.Now().UTC(),
+	}
+	s.domainAllows[allow.ID] = allow
+
+	copied := *allow
+
+	return &copied
+}
+
+// DomainAllow returns a copy of the allow for domain, or nil if the domain isn't allowed.
+func (s *Server) DomainAllow(domain string) *DomainAllow {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, allow := range s.domainAllows {
+		if allow.Domain == domain {
+			copied := *allow
+
+			return &copied
+		}
+	}
+
+	return nil
+}
+
+// RemoveDomainAllow deletes the allow for domain.
+func (s *Server) RemoveDomainAllow(domain string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for id, allow := range s.domainAllows {
+		if allow.Domain == domain {
+			delete(s.domainAllows, id)
+		}
+	}
+}
+
+func (s *Server) handleAdminDomainAllows(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		if _, _, ok := s.authenticateUser(w, r, "admin:read:domain_allows"); !ok {
+			return
+		}
+
+		ids := make([]string, 0, len(s.domainAllows))
+		for id := range s.domainAllows {
+			ids = append(ids, id)
+		}
+
+		allows := []*DomainAllow{}
+		for _, id := range paginate(w, r, ids, 100, 200) {
+			allows = append(allows, s.domainAllows[id])
+		}
+
+		writeJSON(w, http.StatusOK, allows)
+	case http.MethodPost:
+		values, err := params(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+
+			return
+		}
+
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		if _, _, ok := s.authenticateUser(w, r, "admin:write:domain_allows"); !ok {
+			return
+		}
+
+		domain := strings.ToLower(strings.TrimSpace(values.Get("domain")))
+		if domain == "" {
+			writeError(w, http.StatusUnprocessableEntity, "Validation failed: Domain can't be blank")
+
+			return
+		}
+		for _, existing := range s.domainAllows {
+			if existing.Domain == domain {
+				writeError(w, http.StatusUnprocessableEntity, "Validation failed: Domain has already been taken")
+
+				return
+			}
+		}
+
+		allow := &DomainAllow{
+			ID:        s.newID(),
+			Domain:    domain,
+			CreatedAt: time.Now().UTC(),
+		}
+		s.domainAllows[allow.ID] = allow
+
+		writeJSON(w, http.StatusOK, allow)
+	default:
+		writeMethodNotAllowed(w)
+	}
+}
+
+func (s *Server) handleAdminDomainAllow(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/api/v1/admin/domain_allows/")
+
+	scope := "admin:write:domain_allows"
+	if r.Method == http.MethodGet {
+		scope = "admin:read:domain_allows"
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, _, ok := s.authenticateUser(w, r, scope); !ok {
+		return
+	}
+
+	allow, ok := s.domainAllows[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Record not found")
+
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, allow)
+	case http.MethodDelete:
+		delete(s.domainAllows, id)
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{})
+	default:
+		writeMethodNotAllowed(w)
+	}
+}
@@ -0,0 +1,223 @@
+package fakemastodon
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// AddDomainBlock creates a domain block, filling in its id and creation time.
+func (s *Server) AddDomainBlock(block DomainBlock) *DomainBlock {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	block.ID = s.newID()
+	block.CreatedAt = time.Now().UTC()
+	if block.Severity == "" {
+		block.Severity = "silence"
+	}
+	s.domainBlocks[block.ID] = &block
+
+	return &block
+}
+
+// DomainBlock returns a copy of the block for domain, or nil if the domain isn't blocked.
+func (s *Server) DomainBlock(domain string) *DomainBlock {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, block := range s.domainBlocks {
+		if block.Domain == domain {
+			copied := *block
+
+			return &copied
+		}
+	}
+
+	return nil
+}
+
+// DomainBlocks returns the number of domain blocks.
+func (s *Server) DomainBlocks() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return len(s.domainBlocks)
+}
+
+// UpdateDomainBlock calls f with the block for domain so tests can simulate changes made outside the provider.
+func (s *Server) UpdateDomainBlock(domain string, f func(block *DomainBlock)) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, block := range s.domainBlocks {
+		if block.Domain == domain {
+			f(block)
+		}
+	}
+}
+
+// RemoveDomainBlock deletes the block for domain.
+func (s *Server) RemoveDomainBlock(domain string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for id, block := range s.domainBlocks {
+		if block.Domain == domain {
+			delete(s.domainBlocks, id)
+		}
+	}
+}
+
+func (s *Server) handleAdminDomainBlocks(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		if _, _, ok := s.authenticateUser(w, r, "admin:read:domain_blocks"); !ok {
+			return
+		}
+
+		ids := make([]string, 0, len(s.domainBlocks))
+		for id := range s.domainBlocks {
+			ids = append(ids, id)
+		}
+
+		blocks := []*DomainBlock{}
+		for _, id := range paginate(w, r, ids, 100, 200) {
+			blocks = append(blocks, s.domainBlocks[id])
+		}
+
+		writeJSON(w, http.StatusOK, blocks)
+	case http.MethodPost:
+		values, err := params(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+
+			return
+		}
+
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		if _, _, ok := s.authenticateUser(w, r, "admin:write:domain_blocks"); !ok {
+			return
+		}
+
+		domain := strings.ToLower(strings.TrimSpace(values.Get("domain")))
+		if domain == "" {
+			writeError(w, http.StatusUnprocessableEntity, "Validation failed: Domain can't be blank")
+
+			return
+		}
+		for _, existing := range s.domainBlocks {
+			if existing.Domain == domain {
+				writeJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{
+					"error":                 "You have already imposed stricter limits on " + domain + ".",
+					"existing_domain_block": existing,
+				})
+
+				return
+			}
+		}
+
+		block := &DomainBlock{
+			ID:        s.newID(),
+			Domain:    domain,
+			CreatedAt: time.Now().UTC(),
+			Severity:  "silence",
+		}
+		if !applyDomainBlockParams(w, block, values) {
+			return
+		}
+		s.domainBlocks[block.ID] = block
+
+		writeJSON(w, http.StatusOK, block)
+	default:
+		writeMethodNotAllowed(w)
+	}
+}
+
+func (s *Server) handleAdminDomainBlock(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/api/v1/admin/domain_blocks/")
+
+	scope := "admin:write:domain_blocks"
+	if r.Method == http.MethodGet {
+		scope = "admin:read:domain_blocks"
+	}
+
+	values, err := params(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, _, ok := s.authenticateUser(w, r, scope); !ok {
+		return
+	}
+
+	block, ok := s.domainBlocks[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Record not found")
+
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, block)
+	case http.MethodPut:
+		updated := *block
+		if !applyDomainBlockParams(w, &updated, values) {
+			return
+		}
+		*block = updated
+
+		writeJSON(w, http.StatusOK, block)
+	case http.MethodDelete:
+		delete(s.domainBlocks, id)
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{})
+	default:
+		writeMethodNotAllowed(w)
+	}
+}
+
+func applyDomainBlockParams(w http.ResponseWriter, block *DomainBlock, values url.Values) bool {
+	get := func(key string) (string, bool) {
+		v, ok := values[key]
+		if !ok || len(v) == 0 {
+			return "", false
+		}
+
+		return v[0], true
+	}
+
+	if v, ok := get("severity"); ok {
+		switch v {
+		case "noop", "silence", "suspend":
+			block.Severity = v
+		default:
+			writeError(w, http.StatusUnprocessableEntity, "Validation failed: Severity is not included in the list")
+
+			return false
+		}
+	}
+	block.RejectMedia = boolParam(values, "reject_media", block.RejectMedia)
+	block.RejectReports = boolParam(values, "reject_reports", block.RejectReports)
+	block.Obfuscate = boolParam(values, "obfuscate", block.Obfuscate)
+	if v, ok := get("private_comment"); ok {
+		block.PrivateComment = &v
+	}
+	if v, ok := get("public_comment"); ok {
+		block.PublicComment = &v
+	}
+
+	return true
+}
@@ -0,0 +1,150 @@
+package fakemastodon
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EmailDomainBlock returns a copy of the block for domain, or nil if the domain isn't blocked.
+func (s *Server) EmailDomainBlock(domain string) *EmailDomainBlock {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, block := range s.emailDomainBlocks {
+		if block.Domain == domain {
+			copied := *block
+
+			return &copied
+		}
+	}
+
+	return nil
+}
+
+// RemoveEmailDomainBlock deletes the block for domain.
+func (s *Server) RemoveEmailDomainBlock(domain string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for id, block := range s.emailDomainBlocks {
+		if block.Domain == domain {
+			delete(s.emailDomainBlocks, id)
+		}
+	}
+}
+
+// newEmailDomainBlock creates a block with a week of empty history. Callers must hold the lock.
+func (s *Server) newEmailDomainBlock(domain string) *EmailDomainBlock {
+	block := &EmailDomainBlock{
+		ID:        s.newID(),
+		Domain:    domain,
+		CreatedAt: time.Now().UTC(),
+		History:   []EmailDomainBlockHistory{},
+	}
+
+	day := time.Now().UTC().Truncate(24 * time.Hour)
+	for i := 0; i < 7; i++ {
+		block.History = append(block.History, EmailDomainBlockHistory{
+			Day:      strconv.FormatInt(day.AddDate(0, 0, -i).Unix(), 10),
+			Accounts: "0",
+			Uses:     "0",
+		})
+	}
+	s.emailDomainBlocks[block.ID] = block
+
+	return block
+}
+
+func (s *Server) handleAdminEmailDomainBlocks(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		if _, _, ok := s.authenticateUser(w, r, "admin:read:email_domain_blocks"); !ok {
+			return
+		}
+
+		ids := make([]string, 0, len(s.emailDomainBlocks))
+		for id := range s.emailDomainBlocks {
+			ids = append(ids, id)
+		}
+
+		blocks := []*EmailDomainBlock{}
+		for _, id := range paginate(w, r, ids, 100, 200) {
+			blocks = append(blocks, s.emailDomainBlocks[id])
+		}
+
+		writeJSON(w, http.StatusOK, blocks)
+	case http.MethodPost:
+		values, err := params(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+
+			return
+		}
+
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		if _, _, ok := s.authenticateUser(w, r, "admin:write:email_domain_blocks"); !ok {
+			return
+		}
+
+		domain := strings.ToLower(strings.TrimSpace(values.Get("domain")))
+		if domain == "" {
+			writeError(w, http.StatusUnprocessableEntity, "Validation failed: Domain can't be blank")
+
+			return
+		}
+		for _, existing := range s.emailDomainBlocks {
+			if existing.Domain == domain {
+				writeError(w, http.StatusUnprocessableEntity, "Validation failed: Domain has already been taken")
+
+				return
+			}
+		}
+
+		block := s.newEmailDomainBlock(domain)
+
+		writeJSON(w, http.StatusOK, block)
+	default:
+		writeMethodNotAllowed(w)
+	}
+}
+
+func (s *Server) handleAdminEmailDomainBlock(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/api/v1/admin/email_domain_blocks/")
+
+	scope := "admin:write:email_domain_blocks"
+	if r.Method == http.MethodGet {
+		scope = "admin:read:email_domain_blocks"
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, _, ok := s.authenticateUser(w, r, scope); !ok {
+		return
+	}
+
+	block, ok := s.emailDomainBlocks[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Record not found")
+
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, block)
+	case http.MethodDelete:
+		delete(s.emailDomainBlocks, id)
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{})
+	default:
+		writeMethodNotAllowed(w)
+	}
+}
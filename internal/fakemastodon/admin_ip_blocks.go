@@ -0,0 +1,245 @@
+package fakemastodon
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IPBlock returns a copy of the block for ip, which must include the prefix length, or nil if it isn't
+// blocked.
+func (s *Server) IPBlock(ip string) *IPBlock {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, block := range s.ipBlocks {
+		if block.IP == ip {
+			copied := *block
+
+			return &copied
+		}
+	}
+
+	return nil
+}
+
+// UpdateIPBlock calls f with the block for ip so tests can simulate changes made outside the provider.
+func (s *Server) UpdateIPBlock(ip string, f func(block *IPBlock)) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, block := range s.ipBlocks {
+		if block.IP == ip {
+			f(block)
+		}
+	}
+}
+
+// RemoveIPBlock deletes the block for ip.
+func (s *Server) RemoveIPBlock(ip string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for id, block := range s.ipBlocks {
+		if block.IP == ip {
+			delete(s.ipBlocks, id)
+		}
+	}
+}
+
+// purgeExpiredIPBlocks deletes blocks past their expiry, like Mastodon's scheduled cleanup. Callers must
+// hold the lock.
+func (s *Server) purgeExpiredIPBlocks() {
+	now := time.Now()
+	for id, block := range s.ipBlocks {
+		if block.ExpiresAt != nil && block.ExpiresAt.Before(now) {
+			delete(s.ipBlocks, id)
+		}
+	}
+}
+
+func (s *Server) handleAdminIPBlocks(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		if _, _, ok := s.authenticateUser(w, r, "admin:read:ip_blocks"); !ok {
+			return
+		}
+		s.purgeExpiredIPBlocks()
+
+		ids := make([]string, 0, len(s.ipBlocks))
+		for id := range s.ipBlocks {
+			ids = append(ids, id)
+		}
+
+		blocks := []*IPBlock{}
+		for _, id := range paginate(w, r, ids, 100, 200) {
+			blocks = append(blocks, s.ipBlocks[id])
+		}
+
+		writeJSON(w, http.StatusOK, blocks)
+	case http.MethodPost:
+		values, err := params(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+
+			return
+		}
+
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		if _, _, ok := s.authenticateUser(w, r, "admin:write:ip_blocks"); !ok {
+			return
+		}
+		s.purgeExpiredIPBlocks()
+
+		ip, ok := normalizeIP(values.Get("ip"))
+		if !ok {
+			writeError(w, http.StatusUnprocessableEntity, "Validation failed: Ip is invalid")
+
+			return
+		}
+		for _, existing := range s.ipBlocks {
+			if existing.IP == ip {
+				writeError(w, http.StatusUnprocessableEntity, "Validation failed: Ip has already been taken")
+
+				return
+			}
+		}
+
+		block := &IPBlock{
+			ID:        s.newID(),
+			IP:        ip,
+			CreatedAt: time.Now().UTC(),
+		}
+		if !applyIPBlockParams(w, block, values) {
+			return
+		}
+		if block.Severity == "" {
+			writeError(w, http.StatusUnprocessableEntity, "Validation failed: Severity can't be blank")
+
+			return
+		}
+		s.ipBlocks[block.ID] = block
+
+		writeJSON(w, http.StatusOK, block)
+	default:
+		writeMethodNotAllowed(w)
+	}
+}
+
+func (s *Server) handleAdminIPBlock(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/api/v1/admin/ip_blocks/")
+
+	scope := "admin:write:ip_blocks"
+	if r.Method == http.MethodGet {
+		scope = "admin:read:ip_blocks"
+	}
+
+	values, err := params(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, _, ok := s.authenticateUser(w, r, scope); !ok {
+		return
+	}
+	s.purgeExpiredIPBlocks()
+
+	block, ok := s.ipBlocks[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Record not found")
+
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, block)
+	case http.MethodPut:
+		updated := *block
+		if v := values.Get("ip"); v != "" {
+			ip, ok := normalizeIP(v)
+			if !ok {
+				writeError(w, http.StatusUnprocessableEntity, "Validation failed: Ip is invalid")
+
+				return
+			}
+			updated.IP = ip
+		}
+		if !applyIPBlockParams(w, &updated, values) {
+			return
+		}
+		*block = updated
+
+		writeJSON(w, http.StatusOK, block)
+	case http.MethodDelete:
+		delete(s.ipBlocks, id)
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{})
+	default:
+		writeMethodNotAllowed(w)
+	}
+}
+
+func applyIPBlockParams(w http.ResponseWriter, block *IPBlock, values url.Values) bool {
+	if v := values.Get("severity"); v != "" {
+		switch v {
+		case "sign_up_requires_approval", "sign_up_block", "no_access":
+			block.Severity = v
+		default:
+			writeError(w, http.StatusUnprocessableEntity, "Validation failed: Severity is not included in the list")
+
+			return false
+		}
+	}
+	if v, ok := values["comment"]; ok && len(v) > 0 {
+		block.Comment = v[0]
+	}
+	if v, ok := values["expires_in"]; ok && len(v) > 0 {
+		block.ExpiresAt = nil
+		if v[0] != "" {
+			seconds, err := strconv.Atoi(v[0])
+			if err != nil || seconds <= 0 {
+				writeError(w, http.StatusUnprocessableEntity, "Validation failed: Expires in is invalid")
+
+				return false
+			}
+			expiresAt := time.Now().UTC().Add(time.Duration(seconds) * time.Second)
+			block.ExpiresAt = &expiresAt
+		}
+	}
+
+	return true
+}
+
+// normalizeIP returns the network address of ip with its prefix length, the way Mastodon returns blocked
+// addresses.
+func normalizeIP(ip string) (string, bool) {
+	if _, network, err := net.ParseCIDR(ip); err == nil {
+		ones, _ := network.Mask.Size()
+
+		return network.IP.String() + "/" + strconv.Itoa(ones), true
+	}
+
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return "", false
+	}
+	if addr.To4() != nil {
+		return addr.String() + "/32", true
+	}
+
+	return addr.String() + "/128", true
+}
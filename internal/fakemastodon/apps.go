@@ -0,0 +1,368 @@
+package fakemastodon
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// AddApp registers an application with the given space separated scopes.
+func (s *Server) AddApp(name, scopes string) *Application {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.addApp(name, "", "urn:ietf:wg:oauth:2.0:oob", scopes)
+}
+
+// RemoveApp deletes the application with clientID and every token issued to it.
+func (s *Server) RemoveApp(clientID string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.apps, clientID)
+	for k, t := range s.tokens {
+		if t.ClientID == clientID {
+			delete(s.tokens, k)
+		}
+	}
+}
+
+// App returns the application with clientID, or nil if it doesn't exist.
+func (s *Server) App(clientID string) *Application {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.apps[clientID]
+}
+
+// Apps returns the number of registered applications.
+func (s *Server) Apps() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return len(s.apps)
+}
+
+// AddToken issues a token with the given scopes to the application with clientID. If accountID
+// is set the token acts on behalf of that account.
+func (s *Server) AddToken(clientID, accountID string, scopes ...string) string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.addToken(clientID, accountID, scopes)
+}
+
+// AddAdminToken creates an admin account with an application and returns a token granting it
+// every scope, for testing admin endpoints.
+func (s *Server) AddAdminToken() string {
+	account := s.AddAccount("admin")
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	scopes := []string{"read", "write", "follow", "admin:read", "admin:write"}
+	app := s.addApp("admin", "", "urn:ietf:wg:oauth:2.0:oob", strings.Join(scopes, " "))
+
+	return s.addToken(app.ClientID, account.ID, scopes)
+}
+
+// Tokens returns the number of valid tokens issued to the application with clientID.
+func (s *Server) Tokens(clientID string) int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	count := 0
+	for _, t := range s.tokens {
+		if t.ClientID == clientID {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Revoked returns the number of tokens issued to the application with clientID that were revoked
+// through the API.
+func (s *Server) Revoked(clientID string) int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.revoked[clientID]
+}
+
+// RevokeTokens invalidates every token issued to the application with clientID.
+func (s *Server) RevokeTokens(clientID string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for k, t := range s.tokens {
+		if t.ClientID == clientID {
+			delete(s.tokens, k)
+		}
+	}
+}
+
+func (s *Server) addApp(name, website, redirectURI, scopes string) *Application {
+	app := &Application{
+		ID:           s.newID(),
+		Name:         name,
+		Website:      website,
+		RedirectURI:  redirectURI,
+		ClientID:     randomString(),
+		ClientSecret: randomString(),
+		VapidKey:     randomString(),
+		Scopes:       scopes,
+	}
+	s.apps[app.ClientID] = app
+
+	return app
+}
+
+func (s *Server) addToken(clientID, accountID string, scopes []string) string {
+	token := &Token{
+		AccessToken: randomString(),
+		ClientID:    clientID,
+		Scopes:      scopes,
+		AccountID:   accountID,
+		CreatedAt:   time.Now(),
+	}
+	s.tokens[token.AccessToken] = token
+
+	return token.AccessToken
+}
+
+// authenticate returns the request's token if it grants scope, writing an error response otherwise.
+// Callers must hold the lock.
+func (s *Server) authenticate(w http.ResponseWriter, r *http.Request, scope string) (*Token, bool) {
+	token, ok := s.tokens[strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")]
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "The access token is invalid")
+
+		return nil, false
+	}
+
+	if !hasScope(token.Scopes, scope) {
+		writeError(w, http.StatusForbidden, "This action is outside the authorized scopes")
+
+		return nil, false
+	}
+
+	return token, true
+}
+
+// authenticateUser is like authenticate but also requires the token to belong to an account.
+func (s *Server) authenticateUser(w http.ResponseWriter, r *http.Request, scope string) (*Token, *Account, bool) {
+	token, ok := s.authenticate(w, r, scope)
+	if !ok {
+		return nil, nil, false
+	}
+
+	account, ok := s.accounts[token.AccountID]
+	if !ok {
+		writeError(w, http.StatusUnprocessableEntity, "This method requires an authenticated user")
+
+		return nil, nil, false
+	}
+
+	return token, account, true
+}
+
+// hasScope returns true if granted includes required or one of its parent scopes.
+func hasScope(granted []string, required string) bool {
+	if required == "" {
+		return true
+	}
+
+	for _, g := range granted {
+		if g == required || strings.HasPrefix(required, g+":") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Server) handleApps(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeMethodNotAllowed(w)
+
+		return
+	}
+
+	values, err := params(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	if values.Get("client_name") == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Validation failed: Application name can't be blank")
+
+		return
+	}
+	if values.Get("redirect_uris") == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Validation failed: Redirect URI can't be blank")
+
+		return
+	}
+
+	scopes := values.Get("scopes")
+	if scopes == "" {
+		scopes = "read"
+	}
+
+	s.lock.Lock()
+	app := s.addApp(values.Get("client_name"), values.Get("website"), values.Get("redirect_uris"), scopes)
+	s.lock.Unlock()
+
+	writeJSON(w, http.StatusOK, app)
+}
+
+func (s *Server) handleAppsVerifyCredentials(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeMethodNotAllowed(w)
+
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	token, ok := s.authenticate(w, r, "")
+	if !ok {
+		return
+	}
+
+	app, ok := s.apps[token.ClientID]
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "The access token is invalid")
+
+		return
+	}
+
+	if s.LegacyAppCredentials {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"name":      app.Name,
+			"website":   app.Website,
+			"vapid_key": app.VapidKey,
+		})
+
+		return
+	}
+
+	// like Mastodon 4.3 and later, which also reports the registration
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"id":            app.ID,
+		"name":          app.Name,
+		"website":       app.Website,
+		"scopes":        strings.Fields(app.Scopes),
+		"redirect_uri":  app.RedirectURI,
+		"redirect_uris": strings.Split(app.RedirectURI, "\n"),
+		"vapid_key":     app.VapidKey,
+	})
+}
+
+func (s *Server) handleOAuthToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeMethodNotAllowed(w)
+
+		return
+	}
+
+	values, err := params(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	app, ok := s.apps[values.Get("client_id")]
+	if !ok || app.ClientSecret != values.Get("client_secret") {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{
+			"error":             "invalid_client",
+			"error_description": "Client authentication failed due to unknown client, no client authentication included, or unsupported authentication method.",
+		})
+
+		return
+	}
+
+	if values.Get("grant_type") != "client_credentials" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error":             "unsupported_grant_type",
+			"error_description": "The authorization grant type is not supported by the authorization server.",
+		})
+
+		return
+	}
+
+	scopes := strings.Fields(values.Get("scope"))
+	if len(scopes) == 0 {
+		scopes = []string{"read"}
+	}
+	appScopes := strings.Fields(app.Scopes)
+	for _, scope := range scopes {
+		if !hasScope(appScopes, scope) {
+			writeJSON(w, http.StatusBadRequest, map[string]string{
+				"error":             "invalid_scope",
+				"error_description": "The requested scope is invalid, unknown, or malformed.",
+			})
+
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": s.addToken(app.ClientID, "", scopes),
+		"token_type":   "Bearer",
+		"scope":        strings.Join(scopes, " "),
+		"created_at":   time.Now().Unix(),
+	})
+}
+
+func (s *Server) handleOAuthRevoke(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeMethodNotAllowed(w)
+
+		return
+	}
+
+	values, err := params(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	app, ok := s.apps[values.Get("client_id")]
+	if !ok || app.ClientSecret != values.Get("client_secret") {
+		writeJSON(w, http.StatusForbidden, map[string]string{
+			"error":             "unauthorized_client",
+			"error_description": "You are not authorized to revoke this token",
+		})
+
+		return
+	}
+
+	if token, ok := s.tokens[values.Get("token")]; ok && token.ClientID == app.ClientID {
+		delete(s.tokens, token.AccessToken)
+		s.revoked[app.ClientID]++
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{})
+}
+
+func randomString() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
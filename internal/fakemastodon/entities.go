@@ -0,0 +1,198 @@
+package fakemastodon
+
+import (
+	"time"
+)
+
+// Application is a registered OAuth application.
+type Application struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Website      string `json:"website"`
+	RedirectURI  string `json:"redirect_uri"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	VapidKey     string `json:"vapid_key"`
+
+	Scopes string `json:"-"`
+}
+
+// Token is an issued OAuth access token. Tokens without an account are application tokens.
+type Token struct {
+	AccessToken string
+	ClientID    string
+	Scopes      []string
+	AccountID   string
+	CreatedAt   time.Time
+}
+
+// Account is a user account.
+type Account struct {
+	ID             string    `json:"id"`
+	Username       string    `json:"username"`
+	Acct           string    `json:"acct"`
+	DisplayName    string    `json:"display_name"`
+	Locked         bool      `json:"locked"`
+	Bot            bool      `json:"bot"`
+	Discoverable   bool      `json:"discoverable"`
+	Group          bool      `json:"group"`
+	CreatedAt      time.Time `json:"created_at"`
+	Note           string    `json:"note"`
+	URL            string    `json:"url"`
+	Avatar         string    `json:"avatar"`
+	AvatarStatic   string    `json:"avatar_static"`
+	Header         string    `json:"header"`
+	HeaderStatic   string    `json:"header_static"`
+	FollowersCount int64     `json:"followers_count"`
+	FollowingCount int64     `json:"following_count"`
+	StatusesCount  int64     `json:"statuses_count"`
+	LastStatusAt   *string   `json:"last_status_at"`
+	Emojis         []Emoji   `json:"emojis"`
+	Fields         []Field   `json:"fields"`
+	Moved          *Account  `json:"moved,omitempty"`
+	Roles          []Role    `json:"roles"`
+
+	Indexable       bool `json:"indexable"`
+	HideCollections bool `json:"hide_collections"`
+
+	// Source is only returned to the account itself.
+	Source AccountSource `json:"-"`
+}
+
+// AccountSource is the plain text profile and posting defaults of an account.
+type AccountSource struct {
+	Privacy         string  `json:"privacy"`
+	Sensitive       bool    `json:"sensitive"`
+	Language        *string `json:"language"`
+	Note            string  `json:"note"`
+	Fields          []Field `json:"fields"`
+	Discoverable    bool    `json:"discoverable"`
+	HideCollections bool    `json:"hide_collections"`
+	Indexable       bool    `json:"indexable"`
+}
+
+// Role is a role shown on an account's profile.
+type Role struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// Emoji is a custom emoji.
+type Emoji struct {
+	Shortcode       string `json:"shortcode"`
+	URL             string `json:"url"`
+	StaticURL       string `json:"static_url"`
+	VisibleInPicker bool   `json:"visible_in_picker"`
+}
+
+// Field is a profile metadata field.
+type Field struct {
+	Name       string     `json:"name"`
+	Value      string     `json:"value"`
+	VerifiedAt *time.Time `json:"verified_at"`
+}
+
+// Status is a post.
+type Status struct {
+	ID          string     `json:"id"`
+	URI         string     `json:"uri"`
+	URL         string     `json:"url"`
+	Account     *Account   `json:"account"`
+	InReplyToID *string    `json:"in_reply_to_id"`
+	Content     string     `json:"content"`
+	CreatedAt   time.Time  `json:"created_at"`
+	EditedAt    *time.Time `json:"edited_at"`
+	Sensitive   bool       `json:"sensitive"`
+	SpoilerText string     `json:"spoiler_text"`
+	Visibility  string     `json:"visibility"`
+	Language    *string    `json:"language"`
+
+	// Text is the plain text the status was posted with, only returned by its source.
+	Text string `json:"-"`
+	// Pinned is only returned to the account that posted the status.
+	Pinned bool `json:"-"`
+}
+
+// Instance is the server's instance information.
+type Instance struct {
+	URI         string `json:"uri"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Email       string `json:"email"`
+	Version     string `json:"version"`
+	Thumbnail   string `json:"thumbnail"`
+
+	Configuration InstanceConfiguration `json:"configuration"`
+}
+
+// InstanceConfiguration is the server's limits.
+type InstanceConfiguration struct {
+	Accounts         InstanceAccountsConfiguration         `json:"accounts"`
+	MediaAttachments InstanceMediaAttachmentsConfiguration `json:"media_attachments"`
+}
+
+// InstanceAccountsConfiguration is the server's limits on accounts. Mastodon doesn't report the number of
+// profile fields, other servers do.
+type InstanceAccountsConfiguration struct {
+	MaxFeaturedTags  int `json:"max_featured_tags"`
+	MaxProfileFields int `json:"max_profile_fields,omitempty"`
+}
+
+// InstanceMediaAttachmentsConfiguration is the server's limits on uploaded media, which also apply to
+// avatars and headers.
+type InstanceMediaAttachmentsConfiguration struct {
+	ImageSizeLimit     int64    `json:"image_size_limit"`
+	SupportedMimeTypes []string `json:"supported_mime_types"`
+}
+
+// DomainBlock is an admin domain block.
+type DomainBlock struct {
+	ID             string    `json:"id"`
+	Domain         string    `json:"domain"`
+	CreatedAt      time.Time `json:"created_at"`
+	Severity       string    `json:"severity"`
+	RejectMedia    bool      `json:"reject_media"`
+	RejectReports  bool      `json:"reject_reports"`
+	PrivateComment *string   `json:"private_comment"`
+	PublicComment  *string   `json:"public_comment"`
+	Obfuscate      bool      `json:"obfuscate"`
+}
+
+// DomainAllow is an admin domain allow.
+type DomainAllow struct {
+	ID        string    `json:"id"`
+	Domain    string    `json:"domain"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// EmailDomainBlock is an admin email domain block.
+type EmailDomainBlock struct {
+	ID        string                    `json:"id"`
+	Domain    string                    `json:"domain"`
+	CreatedAt time.Time                 `json:"created_at"`
+	History   []EmailDomainBlockHistory `json:"history"`
+}
+
+// EmailDomainBlockHistory is the daily sign up activity of a blocked email domain.
+type EmailDomainBlockHistory struct {
+	Day      string `json:"day"`
+	Accounts string `json:"accounts"`
+	Uses     string `json:"uses"`
+}
+
+// IPBlock is an admin IP block.
+type IPBlock struct {
+	ID        string     `json:"id"`
+	IP        string     `json:"ip"`
+	Severity  string     `json:"severity"`
+	Comment   string     `json:"comment"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// CanonicalEmailBlock is an admin canonical email block.
+type CanonicalEmailBlock struct {
+	ID                 string `json:"id"`
+	CanonicalEmailHash string `json:"canonical_email_hash"`
+}
@@ -0,0 +1,86 @@
+package fakemastodon
+
+import (
+	"net/http"
+	"strings"
+)
+
+// fault is an error response returned in place of handling a matching request.
+type fault struct {
+	method     string
+	pathPrefix string
+	status     int
+	message    string
+	remaining  int
+}
+
+// Fail makes the next times requests matching method and pathPrefix fail with status. An empty method
+// matches every method. A negative times fails every matching request until ClearFaults is called.
+func (s *Server) Fail(method, pathPrefix string, status, times int) {
+	s.FailWithMessage(method, pathPrefix, status, times, "")
+}
+
+// FailWithMessage is like Fail with a custom error message in the response body.
+func (s *Server) FailWithMessage(method, pathPrefix string, status, times int, message string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.faults = append(s.faults, &fault{
+		method:     method,
+		pathPrefix: pathPrefix,
+		status:     status,
+		message:    message,
+		remaining:  times,
+	})
+}
+
+// FailUnauthorized makes the next matching request fail with 401 Unauthorized.
+func (s *Server) FailUnauthorized(method, pathPrefix string) {
+	s.FailWithMessage(method, pathPrefix, http.StatusUnauthorized, 1, "The access token is invalid")
+}
+
+// FailNotFound makes the next matching request fail with 404 Not Found.
+func (s *Server) FailNotFound(method, pathPrefix string) {
+	s.FailWithMessage(method, pathPrefix, http.StatusNotFound, 1, "Record not found")
+}
+
+// FailUnprocessable makes the next matching request fail with 422 Unprocessable Entity.
+func (s *Server) FailUnprocessable(method, pathPrefix, message string) {
+	s.FailWithMessage(method, pathPrefix, http.StatusUnprocessableEntity, 1, "Validation failed: "+message)
+}
+
+// FailRateLimited makes the next times matching requests fail with 429 Too Many Requests.
+func (s *Server) FailRateLimited(method, pathPrefix string, times int) {
+	s.FailWithMessage(method, pathPrefix, http.StatusTooManyRequests, times, "Too many requests")
+}
+
+// ClearFaults removes every pending fault.
+func (s *Server) ClearFaults() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.faults = nil
+}
+
+// matchFault returns the first fault matching r, consuming one of its uses. Callers must hold the lock.
+func (s *Server) matchFault(r *http.Request) *fault {
+	for i, f := range s.faults {
+		if f.method != "" && f.method != r.Method {
+			continue
+		}
+		if !strings.HasPrefix(r.URL.Path, f.pathPrefix) {
+			continue
+		}
+
+		if f.remaining > 0 {
+			f.remaining--
+			if f.remaining == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+
+		return f
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package fakemastodon
+
+import (
+	"fmt"
+	"net/http"
+)
+
+func (s *Server) handleInstance(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeMethodNotAllowed(w)
+
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	writeJSON(w, http.StatusOK, s.Instance)
+}
+
+func (s *Server) handleNodeinfoWellKnown(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"links": []map[string]string{
+			{
+				"rel":  "http://nodeinfo.diaspora.software/ns/schema/2.0",
+				"href": fmt.Sprintf("%s/nodeinfo/2.0", s.URL),
+			},
+		},
+	})
+}
+
+func (s *Server) handleNodeinfo(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"version": "2.0",
+		"software": map[string]string{
+			"name":    "mastodon",
+			"version": s.Instance.Version,
+		},
+		"protocols":         []string{"activitypub"},
+		"openRegistrations": false,
+	})
+}
@@ -0,0 +1,79 @@
+package fakemastodon
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// paginate returns the page of ids requested by r, newest first, and sets the Link header pointing at the
+// neighbouring pages. defaultLimit and maxLimit mirror the limits of the endpoint being faked.
+func paginate(w http.ResponseWriter, r *http.Request, ids []string, defaultLimit, maxLimit int) []string {
+	sort.Slice(ids, func(i, j int) bool {
+		return idLess(ids[j], ids[i])
+	})
+
+	query := r.URL.Query()
+	limit := defaultLimit
+	if l, err := strconv.Atoi(query.Get("limit")); err == nil && l > 0 {
+		limit = l
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	page := make([]string, 0, limit)
+	for _, id := range ids {
+		if maxID := query.Get("max_id"); maxID != "" && !idLess(id, maxID) {
+			continue
+		}
+		if sinceID := query.Get("since_id"); sinceID != "" && !idLess(sinceID, id) {
+			continue
+		}
+		if minID := query.Get("min_id"); minID != "" && !idLess(minID, id) {
+			continue
+		}
+		if len(page) == limit {
+			break
+		}
+		page = append(page, id)
+	}
+
+	if len(page) == 0 {
+		return page
+	}
+
+	var links []string
+	if len(page) == limit && page[len(page)-1] != ids[len(ids)-1] {
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, pageURL(r, "max_id", page[len(page)-1], limit)))
+	}
+	links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, pageURL(r, "min_id", page[0], limit)))
+	w.Header().Set("Link", strings.Join(links, ", "))
+
+	return page
+}
+
+func pageURL(r *http.Request, key, id string, limit int) string {
+	query := url.Values{}
+	for k, v := range r.URL.Query() {
+		if k != "max_id" && k != "since_id" && k != "min_id" {
+			query[k] = v
+		}
+	}
+	query.Set(key, id)
+	query.Set("limit", strconv.Itoa(limit))
+
+	return fmt.Sprintf("http://%s%s?%s", r.Host, r.URL.Path, query.Encode())
+}
+
+// idLess compares numeric ids.
+func idLess(a, b string) bool {
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+
+	return a < b
+}
@@ -0,0 +1,254 @@
+// Package fakemastodon provides a stateful in-memory stand-in for a Mastodon server, so the provider's
+// resources and data sources can be exercised end to end without network access.
+package fakemastodon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimit is the number of requests the server reports as allowed per window.
+const RateLimit = 300
+
+// Server is an in-memory Mastodon server.
+type Server struct {
+	*httptest.Server
+
+	lock sync.Mutex
+
+	// Instance is returned by the instance endpoints.
+	Instance Instance
+
+	// LegacyAppCredentials makes verifying app credentials only report the app's name, website and
+	// vapid key, like Mastodon before 4.3.
+	LegacyAppCredentials bool
+
+	accounts             map[string]*Account
+	apps                 map[string]*Application
+	canonicalEmailBlocks map[string]*CanonicalEmailBlock
+	domainAllows         map[string]*DomainAllow
+	domainBlocks         map[string]*DomainBlock
+	emailDomainBlocks    map[string]*EmailDomainBlock
+	ipBlocks             map[string]*IPBlock
+	remoteAccounts       map[string]*Account
+	revoked              map[string]int
+	statuses             map[string]*Status
+	tokens               map[string]*Token
+
+	faults   []*fault
+	history  []request
+	nextID   int
+	requests int
+}
+
+// request is a request the server received.
+type request struct {
+	method string
+	path   string
+}
+
+// New starts a new server. Callers must call Close when done.
+func New() *Server {
+	s := &Server{
+		Instance: Instance{
+			Title:       "Fake Mastodon",
+			Description: "An in-memory Mastodon server",
+			Email:       "admin@example.com",
+			Version:     "4.1.2",
+			Thumbnail:   "https://example.com/thumbnail.png",
+			Configuration: InstanceConfiguration{
+				Accounts: InstanceAccountsConfiguration{
+					MaxFeaturedTags: 10,
+				},
+				MediaAttachments: InstanceMediaAttachmentsConfiguration{
+					ImageSizeLimit:     16 * 1024 * 1024,
+					SupportedMimeTypes: []string{"image/jpeg", "image/png", "image/gif", "image/webp", "video/mp4"},
+				},
+			},
+		},
+
+		accounts:             map[string]*Account{},
+		apps:                 map[string]*Application{},
+		canonicalEmailBlocks: map[string]*CanonicalEmailBlock{},
+		domainAllows:         map[string]*DomainAllow{},
+		domainBlocks:         map[string]*DomainBlock{},
+		emailDomainBlocks:    map[string]*EmailDomainBlock{},
+		ipBlocks:             map[string]*IPBlock{},
+		remoteAccounts:       map[string]*Account{},
+		revoked:              map[string]int{},
+		statuses:             map[string]*Status{},
+		tokens:               map[string]*Token{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/nodeinfo", s.handleNodeinfoWellKnown)
+	mux.HandleFunc("/nodeinfo/2.0", s.handleNodeinfo)
+	mux.HandleFunc("/oauth/token", s.handleOAuthToken)
+	mux.HandleFunc("/oauth/revoke", s.handleOAuthRevoke)
+	mux.HandleFunc("/api/v1/apps", s.handleApps)
+	mux.HandleFunc("/api/v1/apps/verify_credentials", s.handleAppsVerifyCredentials)
+	mux.HandleFunc("/api/v1/instance", s.handleInstance)
+	mux.HandleFunc("/api/v1/accounts/", s.handleAccounts)
+	mux.HandleFunc("/api/v1/admin/canonical_email_blocks", s.handleAdminCanonicalEmailBlocks)
+	mux.HandleFunc("/api/v1/admin/canonical_email_blocks/", s.handleAdminCanonicalEmailBlock)
+	mux.HandleFunc("/api/v1/admin/domain_allows", s.handleAdminDomainAllows)
+	mux.HandleFunc("/api/v1/admin/domain_allows/", s.handleAdminDomainAllow)
+	mux.HandleFunc("/api/v1/admin/domain_blocks", s.handleAdminDomainBlocks)
+	mux.HandleFunc("/api/v1/admin/domain_blocks/", s.handleAdminDomainBlock)
+	mux.HandleFunc("/api/v1/admin/email_domain_blocks", s.handleAdminEmailDomainBlocks)
+	mux.HandleFunc("/api/v1/admin/email_domain_blocks/", s.handleAdminEmailDomainBlock)
+	mux.HandleFunc("/api/v1/admin/ip_blocks", s.handleAdminIPBlocks)
+	mux.HandleFunc("/api/v1/admin/ip_blocks/", s.handleAdminIPBlock)
+	mux.HandleFunc("/api/v1/preferences", s.handlePreferences)
+	mux.HandleFunc("/api/v1/statuses", s.handleStatuses)
+	mux.HandleFunc("/api/v1/statuses/", s.handleStatus)
+	mux.HandleFunc("/api/v2/instance", s.handleInstance)
+	mux.HandleFunc("/api/v2/search", s.handleSearch)
+
+	s.Server = httptest.NewServer(s.middleware(mux))
+	s.Instance.URI = s.Domain()
+
+	return s
+}
+
+// Domain returns the host and port the server is listening on, suitable for the provider's domain.
+func (s *Server) Domain() string {
+	return strings.TrimPrefix(s.URL, "http://")
+}
+
+// Requests returns the number of requests the server has received.
+func (s *Server) Requests() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.requests
+}
+
+// RequestsMatching returns the number of requests the server has received matching method and
+// pathPrefix. An empty method matches every method.
+func (s *Server) RequestsMatching(method, pathPrefix string) int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	n := 0
+	for _, r := range s.history {
+		if (method == "" || r.method == method) && strings.HasPrefix(r.path, pathPrefix) {
+			n++
+		}
+	}
+
+	return n
+}
+
+func (s *Server) newID() string {
+	s.nextID++
+
+	return strconv.Itoa(s.nextID)
+}
+
+// middleware counts requests, sets the rate limit headers and injects faults.
+func (s *Server) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.lock.Lock()
+		s.requests++
+		s.history = append(s.history, request{method: r.Method, path: r.URL.Path})
+		remaining := RateLimit - s.requests%RateLimit
+		f := s.matchFault(r)
+		s.lock.Unlock()
+
+		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(RateLimit))
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		w.Header().Set("X-RateLimit-Reset", time.Now().Add(5*time.Minute).UTC().Format(time.RFC3339Nano))
+
+		if f != nil {
+			if f.status == http.StatusTooManyRequests {
+				w.Header().Set("X-RateLimit-Remaining", "0")
+				w.Header().Set("X-RateLimit-Reset", time.Now().UTC().Format(time.RFC3339Nano))
+			}
+			writeError(w, f.status, f.message)
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	if message == "" {
+		message = http.StatusText(status)
+	}
+
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+func writeMethodNotAllowed(w http.ResponseWriter) {
+	writeError(w, http.StatusMethodNotAllowed, "")
+}
+
+// params returns the request's parameters, accepting query, form and JSON encoded bodies.
+func params(r *http.Request) (url.Values, error) {
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			return nil, err
+		}
+
+		values := r.URL.Query()
+		for k, v := range body {
+			switch v := v.(type) {
+			case nil:
+			case []interface{}:
+				for _, e := range v {
+					values.Add(k, fmt.Sprint(e))
+				}
+			default:
+				values.Set(k, fmt.Sprint(v))
+			}
+		}
+
+		return values, nil
+	}
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(32 << 20); err != nil {
+			return nil, err
+		}
+
+		return r.Form, nil
+	}
+
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	return r.Form, nil
+}
+
+// boolParam returns the boolean value of key, or def if it isn't set.
+func boolParam(values url.Values, key string, def bool) bool {
+	v := values.Get(key)
+	if v == "" {
+		return def
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return def
+	}
+
+	return b
+}
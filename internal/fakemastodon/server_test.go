@@ -0,0 +1,136 @@
+package fakemastodon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/mattn/go-mastodon"
+)
+
+func TestServer_Apps(t *testing.T) {
+	s := New()
+	defer s.Close()
+
+	ctx := context.Background()
+	app, err := mastodon.RegisterApp(ctx, &mastodon.AppConfig{
+		Server:     s.URL,
+		ClientName: "test",
+		Scopes:     "read admin:read",
+	})
+	if err != nil {
+		t.Fatalf("unable to register app: %s", err)
+	}
+
+	client := mastodon.NewClient(&mastodon.Config{
+		Server:       s.URL,
+		ClientID:     app.ClientID,
+		ClientSecret: app.ClientSecret,
+	})
+	if err := client.AuthenticateApp(ctx); err != nil {
+		t.Fatalf("unable to authenticate app: %s", err)
+	}
+
+	verified, err := client.VerifyAppCredentials(ctx)
+	if err != nil {
+		t.Fatalf("unable to verify app credentials: %s", err)
+	}
+	if verified.Name != "test" {
+		t.Errorf("unexpected app name, got: %s, want: %s", verified.Name, "test")
+	}
+
+	s.RemoveApp(app.ClientID)
+	if _, err := client.VerifyAppCredentials(ctx); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("expected 401 after removing app, got: %v", err)
+	}
+}
+
+func TestServer_Faults(t *testing.T) {
+	s := New()
+	defer s.Close()
+
+	account := s.AddAccount("user")
+	client := mastodon.NewClient(&mastodon.Config{Server: s.URL})
+	ctx := context.Background()
+
+	tables := []struct {
+		inject func()
+		want   string
+	}{
+		{func() { s.FailUnauthorized(http.MethodGet, "/api/v1/accounts/") }, "401"},
+		{func() { s.FailNotFound("", "/api/v1/accounts/") }, "404"},
+		{func() { s.FailUnprocessable(http.MethodGet, "/api/v1/accounts/", "Username is invalid") }, "422"},
+		{func() {}, ""},
+	}
+
+	for i, table := range tables {
+		table.inject()
+		_, err := client.GetAccount(ctx, mastodon.ID(account.ID))
+		if table.want == "" {
+			if err != nil {
+				t.Errorf("[%d] unexpected error: %s", i, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), table.want) {
+			t.Errorf("[%d] expected %s error, got: %v", i, table.want, err)
+		}
+	}
+
+	if n := s.RequestsMatching(http.MethodGet, "/api/v1/accounts/"); n != len(tables) {
+		t.Errorf("unexpected number of requests, got: %d, want: %d", n, len(tables))
+	}
+	if n := s.RequestsMatching(http.MethodPost, ""); n != 0 {
+		t.Errorf("unexpected number of POST requests, got: %d, want: 0", n)
+	}
+}
+
+func TestServer_DomainBlocksPagination(t *testing.T) {
+	s := New()
+	defer s.Close()
+
+	for i := 0; i < 5; i++ {
+		s.AddDomainBlock(DomainBlock{Domain: fmt.Sprintf("%d.example", i)})
+	}
+	token := s.AddAdminToken()
+
+	seen := map[string]bool{}
+	next := s.URL + "/api/v1/admin/domain_blocks?limit=2"
+	for pages := 0; next != ""; pages++ {
+		if pages > 5 {
+			t.Fatalf("pagination didn't terminate")
+		}
+
+		req, _ := http.NewRequest(http.MethodGet, next, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		var blocks []DomainBlock
+		_ = json.NewDecoder(resp.Body).Decode(&blocks)
+		resp.Body.Close()
+		for _, block := range blocks {
+			seen[block.Domain] = true
+		}
+
+		next = ""
+		for _, link := range strings.Split(resp.Header.Get("Link"), ",") {
+			if strings.Contains(link, `rel="next"`) {
+				u := strings.Trim(strings.TrimSpace(strings.Split(link, ";")[0]), "<>")
+				if _, err := url.Parse(u); err == nil {
+					next = u
+				}
+			}
+		}
+	}
+
+	if len(seen) != 5 {
+		t.Errorf("unexpected number of domain blocks, got: %d, want: %d", len(seen), 5)
+	}
+}
@@ -0,0 +1,307 @@
+package fakemastodon
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxPinnedStatuses is the number of statuses an account can pin.
+const maxPinnedStatuses = 5
+
+// AddStatus posts a public status with text as the account with accountID, returning a copy of it.
+func (s *Server) AddStatus(accountID, text string) *Status {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	status := s.newStatus(s.accounts[accountID], text)
+	s.statuses[status.ID] = status
+
+	copied := *status
+
+	return &copied
+}
+
+// Status returns a copy of the status with id, or nil if it doesn't exist.
+func (s *Server) Status(id string) *Status {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	status, ok := s.statuses[id]
+	if !ok {
+		return nil
+	}
+	copied := *status
+
+	return &copied
+}
+
+// UpdateStatus calls f with the status with id, if it exists, so tests can change it behind the
+// provider's back.
+func (s *Server) UpdateStatus(id string, f func(status *Status)) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if status, ok := s.statuses[id]; ok {
+		f(status)
+	}
+}
+
+// newStatus returns a public status with text posted by account, using the account's posting defaults.
+func (s *Server) newStatus(account *Account, text string) *Status {
+	now := time.Now().UTC()
+	id := s.newID()
+
+	account.StatusesCount++
+	lastStatusAt := now.Format("2006-01-02")
+	account.LastStatusAt = &lastStatusAt
+
+	return &Status{
+		ID:         id,
+		URI:        s.URL + "/users/" + account.Username + "/statuses/" + id,
+		URL:        s.URL + "/@" + account.Username + "/" + id,
+		Account:    account,
+		Content:    formatText(text),
+		CreatedAt:  now,
+		Sensitive:  account.Source.Sensitive,
+		Visibility: "public",
+		Language:   account.Source.Language,
+		Text:       text,
+	}
+}
+
+// statusJSON returns the status as shown to the account with viewerID.
+func statusJSON(status *Status, viewerID string) interface{} {
+	var pinned *bool
+	if status.Account.ID == viewerID {
+		pinned = &status.Pinned
+	}
+
+	return struct {
+		*Status
+		Pinned *bool `json:"pinned,omitempty"`
+	}{status, pinned}
+}
+
+// viewer returns the id of the account the request's access token belongs to, or "" if there isn't one.
+func (s *Server) viewer(r *http.Request) string {
+	if token, ok := s.tokens[strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")]; ok {
+		return token.AccountID
+	}
+
+	return ""
+}
+
+func (s *Server) handleStatuses(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeMethodNotAllowed(w)
+
+		return
+	}
+
+	values, err := params(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	_, account, ok := s.authenticateUser(w, r, "write:statuses")
+	if !ok {
+		return
+	}
+
+	// like Mastodon, surrounding whitespace is stripped
+	text := strings.TrimSpace(values.Get("status"))
+	if text == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Validation failed: Text can't be blank")
+
+		return
+	}
+	if len([]rune(text)) > 500 {
+		writeError(w, http.StatusUnprocessableEntity, "Validation failed: Text character limit of 500 exceeded")
+
+		return
+	}
+
+	status := s.newStatus(account, text)
+	status.Visibility = account.Source.Privacy
+	if v := values.Get("visibility"); v != "" {
+		if v != "public" && v != "unlisted" && v != "private" && v != "direct" {
+			writeError(w, http.StatusUnprocessableEntity, "Validation failed: Visibility is not included in the list")
+
+			return
+		}
+		status.Visibility = v
+	}
+	if v := values.Get("in_reply_to_id"); v != "" {
+		if _, ok := s.statuses[v]; !ok {
+			writeError(w, http.StatusNotFound, "Record not found")
+
+			return
+		}
+		status.InReplyToID = &v
+	}
+	if ok := s.applyStatusParams(w, status, values); !ok {
+		return
+	}
+
+	s.statuses[status.ID] = status
+
+	writeJSON(w, http.StatusOK, statusJSON(status, account.ID))
+}
+
+// applyStatusParams sets the attributes of status that can be changed when editing it. It writes an error
+// and returns false if a parameter is invalid.
+func (s *Server) applyStatusParams(w http.ResponseWriter, status *Status, values map[string][]string) bool {
+	if v, ok := values["spoiler_text"]; ok {
+		status.SpoilerText = strings.TrimSpace(v[0])
+	}
+	if v, ok := values["language"]; ok && v[0] != "" {
+		if !isLanguage(v[0]) {
+			writeError(w, http.StatusUnprocessableEntity, "Validation failed: Language is not included in the list")
+
+			return false
+		}
+		language := v[0]
+		status.Language = &language
+	}
+	status.Sensitive = boolParam(values, "sensitive", status.Sensitive)
+
+	// statuses with a content warning are always sensitive
+	if status.SpoilerText != "" {
+		status.Sensitive = true
+	}
+
+	return true
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	id, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/v1/statuses/"), "/")
+
+	values, err := params(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	status, ok := s.statuses[id]
+	viewerID := s.viewer(r)
+	if !ok || (status.Visibility == "private" || status.Visibility == "direct") && status.Account.ID != viewerID {
+		writeError(w, http.StatusNotFound, "Record not found")
+
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, statusJSON(status, viewerID))
+	case action == "source" && r.Method == http.MethodGet:
+		if _, ok := s.authenticateOwner(w, r, status, "read:statuses"); !ok {
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]string{
+			"id":           status.ID,
+			"text":         status.Text,
+			"spoiler_text": status.SpoilerText,
+		})
+	case action == "" && r.Method == http.MethodPut:
+		if _, ok := s.authenticateOwner(w, r, status, "write:statuses"); !ok {
+			return
+		}
+
+		edited := *status
+		if v, ok := values["status"]; ok {
+			text := strings.TrimSpace(v[0])
+			if text == "" {
+				writeError(w, http.StatusUnprocessableEntity, "Validation failed: Text can't be blank")
+
+				return
+			}
+			edited.Text = text
+			edited.Content = formatText(text)
+		}
+		if ok := s.applyStatusParams(w, &edited, values); !ok {
+			return
+		}
+		now := time.Now().UTC()
+		edited.EditedAt = &now
+
+		*status = edited
+
+		writeJSON(w, http.StatusOK, statusJSON(status, viewerID))
+	case action == "" && r.Method == http.MethodDelete:
+		if _, ok := s.authenticateOwner(w, r, status, "write:statuses"); !ok {
+			return
+		}
+
+		delete(s.statuses, id)
+		status.Account.StatusesCount--
+
+		writeJSON(w, http.StatusOK, struct {
+			*Status
+			Text string `json:"text"`
+		}{status, status.Text})
+	case action == "pin" && r.Method == http.MethodPost:
+		account, ok := s.authenticateOwner(w, r, status, "write:accounts")
+		if !ok {
+			return
+		}
+
+		if status.Visibility == "direct" {
+			writeError(w, http.StatusUnprocessableEntity, "Validation failed: Posts with private mentions can't be pinned")
+
+			return
+		}
+		pinned := 0
+		for _, other := range s.statuses {
+			if other.Account.ID == account.ID && other.Pinned && other.ID != status.ID {
+				pinned++
+			}
+		}
+		if pinned >= maxPinnedStatuses {
+			writeError(w, http.StatusUnprocessableEntity, "Validation failed: You have already pinned the maximum number of posts")
+
+			return
+		}
+
+		status.Pinned = true
+
+		writeJSON(w, http.StatusOK, statusJSON(status, viewerID))
+	case action == "unpin" && r.Method == http.MethodPost:
+		if _, ok := s.authenticateOwner(w, r, status, "write:accounts"); !ok {
+			return
+		}
+
+		status.Pinned = false
+
+		writeJSON(w, http.StatusOK, statusJSON(status, viewerID))
+	default:
+		writeError(w, http.StatusNotFound, "Record not found")
+	}
+}
+
+// authenticateOwner authenticates the request like authenticateUser, additionally requiring the user to
+// have posted status.
+func (s *Server) authenticateOwner(w http.ResponseWriter, r *http.Request, status *Status, scope string) (*Account, bool) {
+	_, account, ok := s.authenticateUser(w, r, scope)
+	if !ok {
+		return nil, false
+	}
+
+	if status.Account.ID != account.ID {
+		writeError(w, http.StatusForbidden, "This action is not allowed")
+
+		return nil, false
+	}
+
+	return account, true
+}
@@ -0,0 +1,170 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/mattn/go-mastodon"
+	"time"
+)
+
+// account is an account as returned by the API. It adds the attributes go-mastodon doesn't know about.
+type account struct {
+	mastodon.Account
+
+	Fields       []accountField `json:"fields"`
+	Group        bool           `json:"group"`
+	LastStatusAt *string        `json:"last_status_at"`
+	Moved        *account       `json:"moved"`
+	Roles        []accountRole  `json:"roles"`
+
+	HideCollections bool `json:"hide_collections"`
+	Indexable       bool `json:"indexable"`
+}
+
+// credentialAccount is the account an access token belongs to, which also has its source.
+type credentialAccount struct {
+	account
+
+	Source accountSource `json:"source"`
+}
+
+// accountSource is the plain text profile and posting defaults of an account. Older servers only report
+// discoverable, hide_collections and indexable on the account itself.
+type accountSource struct {
+	Privacy         string         `json:"privacy"`
+	Sensitive       bool           `json:"sensitive"`
+	Language        *string        `json:"language"`
+	Note            string         `json:"note"`
+	Fields          []accountField `json:"fields"`
+	Discoverable    *bool          `json:"discoverable"`
+	HideCollections *bool          `json:"hide_collections"`
+	Indexable       *bool          `json:"indexable"`
+}
+
+// accountField is a profile field. Unlike go-mastodon's field, unverified fields have no verification time.
+type accountField struct {
+	Name       string     `json:"name"`
+	Value      string     `json:"value"`
+	VerifiedAt *time.Time `json:"verified_at"`
+}
+
+// accountRole is a role shown on an account's profile.
+type accountRole struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// accountEmojiTypes are the attribute types of a custom emoji object.
+var accountEmojiTypes = map[string]attr.Type{
+	"shortcode":         types.StringType,
+	"static_url":        types.StringType,
+	"url":               types.StringType,
+	"visible_in_picker": types.BoolType,
+}
+
+// accountFieldTypes are the attribute types of a profile field object.
+var accountFieldTypes = map[string]attr.Type{
+	"name":        types.StringType,
+	"value":       types.StringType,
+	"verified_at": types.StringType,
+}
+
+// accountMovedTypes are the attribute types of the account an account moved to.
+var accountMovedTypes = map[string]attr.Type{
+	"acct": types.StringType,
+	"id":   types.StringType,
+	"url":  types.StringType,
+}
+
+// accountRoleTypes are the attribute types of a role object.
+var accountRoleTypes = map[string]attr.Type{
+	"color": types.StringType,
+	"id":    types.StringType,
+	"name":  types.StringType,
+}
+
+// emojis converts the account's custom emojis to a list of emoji objects.
+func (a *account) emojis() types.List {
+	list := types.List{
+		ElemType: types.ObjectType{AttrTypes: accountEmojiTypes},
+		Elems:    make([]attr.Value, 0, len(a.Emojis)),
+	}
+	for _, emoji := range a.Emojis {
+		list.Elems = append(list.Elems, types.Object{
+			AttrTypes: accountEmojiTypes,
+			Attrs: map[string]attr.Value{
+				"shortcode":         types.String{Value: emoji.ShortCode},
+				"static_url":        types.String{Value: emoji.StaticURL},
+				"url":               types.String{Value: emoji.URL},
+				"visible_in_picker": types.Bool{Value: emoji.VisibleInPicker},
+			},
+		})
+	}
+
+	return list
+}
+
+// fields converts the account's profile fields to a list of field objects.
+func (a *account) fields() types.List {
+	list := types.List{
+		ElemType: types.ObjectType{AttrTypes: accountFieldTypes},
+		Elems:    make([]attr.Value, 0, len(a.Fields)),
+	}
+	for _, field := range a.Fields {
+		verifiedAt := types.String{Null: true}
+		if field.VerifiedAt != nil {
+			verifiedAt = types.String{Value: field.VerifiedAt.Format(time.RFC3339)}
+		}
+
+		list.Elems = append(list.Elems, types.Object{
+			AttrTypes: accountFieldTypes,
+			Attrs: map[string]attr.Value{
+				"name":        types.String{Value: field.Name},
+				"value":       types.String{Value: field.Value},
+				"verified_at": verifiedAt,
+			},
+		})
+	}
+
+	return list
+}
+
+// moved converts the account the account moved to to an object, null if it hasn't moved.
+func (a *account) moved() types.Object {
+	if a.Moved == nil {
+		return types.Object{
+			AttrTypes: accountMovedTypes,
+			Null:      true,
+		}
+	}
+
+	return types.Object{
+		AttrTypes: accountMovedTypes,
+		Attrs: map[string]attr.Value{
+			"acct": types.String{Value: a.Moved.Acct},
+			"id":   types.String{Value: string(a.Moved.ID)},
+			"url":  types.String{Value: a.Moved.URL},
+		},
+	}
+}
+
+// roles converts the account's roles to a list of role objects.
+func (a *account) roles() types.List {
+	list := types.List{
+		ElemType: types.ObjectType{AttrTypes: accountRoleTypes},
+		Elems:    make([]attr.Value, 0, len(a.Roles)),
+	}
+	for _, role := range a.Roles {
+		list.Elems = append(list.Elems, types.Object{
+			AttrTypes: accountRoleTypes,
+			Attrs: map[string]attr.Value{
+				"color": optionalString(&role.Color),
+				"id":    types.String{Value: role.ID},
+				"name":  types.String{Value: role.Name},
+			},
+		})
+	}
+
+	return list
+}
@@ -2,11 +2,14 @@ package provider
 
 import (
 	"context"
-	"fmt"
 	"github.com/mattn/go-mastodon"
+	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -15,6 +18,7 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces
 var _ provider.DataSourceType = accountDataSourceType{}
 var _ datasource.DataSource = accountDataSource{}
+var _ datasource.DataSourceWithValidateConfig = accountDataSource{}
 
 type accountDataSourceType struct{}
 
@@ -24,42 +28,140 @@ func (t accountDataSourceType) GetSchema(_ context.Context) (tfsdk.Schema, diag.
 		MarkdownDescription: "Get account info",
 
 		Attributes: map[string]tfsdk.Attribute{
+			"acct": {
+				MarkdownDescription: "Account handle to look up, such as `user` for a local account or `user@remote.example`. Conflicts with `id`.",
+				Optional:            true,
+				Computed:            true,
+				Type:                types.StringType,
+			},
 			"id": {
-				MarkdownDescription: "identifier",
-				Required:            true,
+				MarkdownDescription: "identifier, specific to the server. Conflicts with `acct`.",
+				Optional:            true,
+				Computed:            true,
 				Type:                types.StringType,
 			},
+			"resolve": {
+				MarkdownDescription: "Search for a remote account the server doesn't know about yet when looking it up by `acct`, requires an access token. Defaults to `false`.",
+				Optional:            true,
+				Type:                types.BoolType,
+			},
 
 			"username": {
-				MarkdownDescription: "Instance Contact Email",
+				MarkdownDescription: "Username, without the domain",
 				Type:                types.StringType,
 				Computed:            true,
 			},
 			"account": {
-				MarkdownDescription: "Instance Thumbnail",
+				MarkdownDescription: "Account handle, the username for local accounts and `username@domain` for remote accounts",
 				Type:                types.StringType,
 				Computed:            true,
 			},
 			"display_name": {
-				MarkdownDescription: "Instance Title",
+				MarkdownDescription: "Display name",
 				Type:                types.StringType,
 				Computed:            true,
 			},
 			"created_at": {
-				MarkdownDescription: "Instance URI",
+				MarkdownDescription: "When the account was created",
 				Type:                types.StringType,
 				Computed:            true,
 			},
 			"url": {
-				MarkdownDescription: "Instance Version",
+				MarkdownDescription: "Profile page",
 				Type:                types.StringType,
 				Computed:            true,
 			},
 			"discoverable": {
-				MarkdownDescription: "Instance Version",
+				MarkdownDescription: "Whether the account is featured in the profile directory",
 				Type:                types.BoolType,
 				Computed:            true,
 			},
+			"note": {
+				MarkdownDescription: "Profile bio, as HTML",
+				Type:                types.StringType,
+				Computed:            true,
+			},
+			"avatar": {
+				MarkdownDescription: "Avatar image URL",
+				Type:                types.StringType,
+				Computed:            true,
+			},
+			"avatar_static": {
+				MarkdownDescription: "Avatar image URL, without animation",
+				Type:                types.StringType,
+				Computed:            true,
+			},
+			"header": {
+				MarkdownDescription: "Header image URL",
+				Type:                types.StringType,
+				Computed:            true,
+			},
+			"header_static": {
+				MarkdownDescription: "Header image URL, without animation",
+				Type:                types.StringType,
+				Computed:            true,
+			},
+			"locked": {
+				MarkdownDescription: "Whether follow requests are reviewed manually",
+				Type:                types.BoolType,
+				Computed:            true,
+			},
+			"bot": {
+				MarkdownDescription: "Whether the account is automated",
+				Type:                types.BoolType,
+				Computed:            true,
+			},
+			"group": {
+				MarkdownDescription: "Whether the account is a group",
+				Type:                types.BoolType,
+				Computed:            true,
+			},
+			"followers_count": {
+				MarkdownDescription: "Number of followers",
+				Type:                types.Int64Type,
+				Computed:            true,
+			},
+			"following_count": {
+				MarkdownDescription: "Number of accounts followed",
+				Type:                types.Int64Type,
+				Computed:            true,
+			},
+			"statuses_count": {
+				MarkdownDescription: "Number of statuses posted",
+				Type:                types.Int64Type,
+				Computed:            true,
+			},
+			"last_status_at": {
+				MarkdownDescription: "Date of the last status, null if the account never posted",
+				Type:                types.StringType,
+				Computed:            true,
+			},
+			"fields": {
+				MarkdownDescription: "Profile fields. `verified_at` is set when the link in `value` links back to the profile.",
+				Type: types.ListType{
+					ElemType: types.ObjectType{AttrTypes: accountFieldTypes},
+				},
+				Computed: true,
+			},
+			"emojis": {
+				MarkdownDescription: "Custom emojis used in the display name and bio",
+				Type: types.ListType{
+					ElemType: types.ObjectType{AttrTypes: accountEmojiTypes},
+				},
+				Computed: true,
+			},
+			"moved": {
+				MarkdownDescription: "Account the account moved to, null if it hasn't moved",
+				Type:                types.ObjectType{AttrTypes: accountMovedTypes},
+				Computed:            true,
+			},
+			"roles": {
+				MarkdownDescription: "Roles shown on the profile",
+				Type: types.ListType{
+					ElemType: types.ObjectType{AttrTypes: accountRoleTypes},
+				},
+				Computed: true,
+			},
 		},
 	}, nil
 }
@@ -73,7 +175,9 @@ func (t accountDataSourceType) NewDataSource(_ context.Context, in provider.Prov
 }
 
 type accountDataSourceData struct {
-	ID types.String `tfsdk:"id"`
+	AcctInput types.String `tfsdk:"acct"`
+	ID        types.String `tfsdk:"id"`
+	Resolve   types.Bool   `tfsdk:"resolve"`
 
 	Username     types.String `tfsdk:"username"`
 	Acct         types.String `tfsdk:"account"`
@@ -81,12 +185,111 @@ type accountDataSourceData struct {
 	CreatedAt    types.String `tfsdk:"created_at"`
 	URL          types.String `tfsdk:"url"`
 	Discoverable types.Bool   `tfsdk:"discoverable"`
+
+	Note           types.String `tfsdk:"note"`
+	Avatar         types.String `tfsdk:"avatar"`
+	AvatarStatic   types.String `tfsdk:"avatar_static"`
+	Header         types.String `tfsdk:"header"`
+	HeaderStatic   types.String `tfsdk:"header_static"`
+	Locked         types.Bool   `tfsdk:"locked"`
+	Bot            types.Bool   `tfsdk:"bot"`
+	Group          types.Bool   `tfsdk:"group"`
+	FollowersCount types.Int64  `tfsdk:"followers_count"`
+	FollowingCount types.Int64  `tfsdk:"following_count"`
+	StatusesCount  types.Int64  `tfsdk:"statuses_count"`
+	LastStatusAt   types.String `tfsdk:"last_status_at"`
+	Fields         types.List   `tfsdk:"fields"`
+	Emojis         types.List   `tfsdk:"emojis"`
+	Moved          types.Object `tfsdk:"moved"`
+	Roles          types.List   `tfsdk:"roles"`
 }
 
 type accountDataSource struct {
 	provider mastodonProvider
 }
 
+func (d accountDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var data accountDataSourceData
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() || data.ID.IsUnknown() || data.AcctInput.IsUnknown() {
+		return
+	}
+
+	if data.ID.IsNull() == data.AcctInput.IsNull() {
+		resp.Diagnostics.AddError(
+			"Invalid Attribute Combination",
+			"Exactly one of id or acct must be set.",
+		)
+
+		return
+	}
+
+	if data.Resolve.Value && data.AcctInput.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("resolve"),
+			"Invalid Attribute Combination",
+			"Attribute resolve can only be set together with acct.",
+		)
+	}
+}
+
+// get returns the account with id.
+func (d accountDataSource) get(ctx context.Context, client *mastodon.Client, id string) (*account, error) {
+	var found account
+	_, err := doAPI(ctx, client, http.MethodGet, "/api/v1/accounts/"+url.PathEscape(id), nil, &found)
+	if err != nil {
+		return nil, err
+	}
+
+	return &found, nil
+}
+
+// lookup returns the account with the handle acct, or nil if it doesn't exist. Remote accounts the server
+// doesn't know about yet are only found if resolve is set.
+func (d accountDataSource) lookup(ctx context.Context, client *mastodon.Client, acct string, resolve bool) (*account, error) {
+	acct = strings.TrimPrefix(acct, "@")
+
+	var found account
+	_, err := doAPI(ctx, client, http.MethodGet, "/api/v1/accounts/lookup", url.Values{"acct": {acct}}, &found)
+	if err == nil {
+		return &found, nil
+	}
+	if !isNotFound(err) {
+		return nil, err
+	}
+	if !resolve {
+		return nil, nil
+	}
+
+	// searching with resolve fetches the account from its server
+	var results struct {
+		Accounts []account `json:"accounts"`
+	}
+	params := url.Values{
+		"q":       {acct},
+		"type":    {"accounts"},
+		"resolve": {"true"},
+		"limit":   {"5"},
+	}
+	_, err = doAPI(ctx, client, http.MethodGet, "/api/v2/search", params, &results)
+	if err != nil {
+		return nil, err
+	}
+
+	// the search also returns partial matches, so only accept the account itself
+	local := strings.TrimSuffix(acct, "@"+d.provider.domain)
+	for i := range results.Accounts {
+		if strings.EqualFold(results.Accounts[i].Acct, local) {
+			return &results.Accounts[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
 func (d accountDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var data accountDataSourceData
 
@@ -97,19 +300,56 @@ func (d accountDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		return
 	}
 
-	account, err := d.provider.newUnauthenticatedClient().GetAccount(ctx, mastodon.ID(data.ID.Value))
+	var found *account
+	var err error
+	if !data.ID.IsNull() {
+		found, err = d.get(ctx, d.provider.newClient(), data.ID.Value)
+	} else {
+		found, err = d.lookup(ctx, d.provider.newClient(), data.AcctInput.Value, data.Resolve.Value)
+	}
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read account, got error: %s", err))
+		addAPIError(&resp.Diagnostics, "read account", err)
 
 		return
 	}
+	if found == nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("acct"),
+			"Account Not Found",
+			"No account exists for "+data.AcctInput.Value+".",
+		)
+
+		return
+	}
+
+	// a configured handle is kept as written, the server may shorten it for local accounts
+	if data.AcctInput.IsNull() {
+		data.AcctInput = types.String{Value: found.Acct}
+	}
+	data.ID = types.String{Value: string(found.ID)}
 
-	data.Username = types.String{Value: account.Username}
-	data.Acct = types.String{Value: account.Acct}
-	data.DisplayName = types.String{Value: account.DisplayName}
-	data.CreatedAt = types.String{Value: account.CreatedAt.String()}
-	data.URL = types.String{Value: account.URL}
-	data.Discoverable = types.Bool{Value: account.Discoverable}
+	data.Username = types.String{Value: found.Username}
+	data.Acct = types.String{Value: found.Acct}
+	data.DisplayName = types.String{Value: found.DisplayName}
+	data.CreatedAt = types.String{Value: found.CreatedAt.String()}
+	data.URL = types.String{Value: found.URL}
+	data.Discoverable = types.Bool{Value: found.Discoverable}
+	data.Note = types.String{Value: found.Note}
+	data.Avatar = types.String{Value: found.Avatar}
+	data.AvatarStatic = types.String{Value: found.AvatarStatic}
+	data.Header = types.String{Value: found.Header}
+	data.HeaderStatic = types.String{Value: found.HeaderStatic}
+	data.Locked = types.Bool{Value: found.Locked}
+	data.Bot = types.Bool{Value: found.Bot}
+	data.Group = types.Bool{Value: found.Group}
+	data.FollowersCount = types.Int64{Value: found.FollowersCount}
+	data.FollowingCount = types.Int64{Value: found.FollowingCount}
+	data.StatusesCount = types.Int64{Value: found.StatusesCount}
+	data.LastStatusAt = optionalString(found.LastStatusAt)
+	data.Fields = found.fields()
+	data.Emojis = found.emojis()
+	data.Moved = found.moved()
+	data.Roles = found.roles()
 
 	diags = resp.State.Set(ctx, &data)
 	resp.Diagnostics.Append(diags...)
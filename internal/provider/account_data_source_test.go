@@ -2,51 +2,150 @@ package provider
 
 import (
 	"fmt"
-	"net/http"
-	"net/http/httptest"
-	"strings"
+	"regexp"
 	"testing"
+	"time"
 
+	"github.com/feditools/terraform-provider-mastodon/internal/fakemastodon"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 )
 
 func TestAccAccountDataSource(t *testing.T) {
-	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		fmt.Fprintln(w, `{"username":"user","acct":"user","display_name":"Cool Dude","created_at":"2019-06-09T00:00:00.000Z","url":"https://example.com/user/tyr","discoverable":true}"`)
-		return
-	}))
-	defer ts.Close()
+	s := fakemastodon.New()
+	defer s.Close()
+
+	moved := s.Account(s.AddAccount("newuser").ID)
+	account := s.AddAccount("user")
+	verifiedAt := time.Date(2022, 11, 5, 12, 0, 0, 0, time.UTC)
+	lastStatusAt := "2022-11-06"
+	s.UpdateAccount(account.ID, func(a *fakemastodon.Account) {
+		a.DisplayName = "Cool Dude :blobcat:"
+		a.Discoverable = true
+		a.Note = "<p>Hello</p>"
+		a.Bot = true
+		a.Locked = true
+		a.FollowersCount = 3
+		a.FollowingCount = 2
+		a.StatusesCount = 1
+		a.LastStatusAt = &lastStatusAt
+		a.Fields = []fakemastodon.Field{
+			{Name: "Website", Value: "https://example.com", VerifiedAt: &verifiedAt},
+			{Name: "Pronouns", Value: "they/them"},
+		}
+		a.Emojis = []fakemastodon.Emoji{
+			{Shortcode: "blobcat", URL: s.URL + "/emoji/blobcat.png", StaticURL: s.URL + "/emoji/blobcat_static.png", VisibleInPicker: true},
+		}
+		a.Roles = []fakemastodon.Role{
+			{ID: "3", Name: "Owner", Color: "#ff0000"},
+		}
+		a.Moved = moved
+	})
+	account = s.Account(account.ID)
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { testAccPreCheck(t) },
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccAccountDataSourceConfig(ts.URL),
+				Config: testAccAccountDataSourceConfig(s, account.ID),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("data.mastodon_account.test", "username", "user"),
 					resource.TestCheckResourceAttr("data.mastodon_account.test", "account", "user"),
-					resource.TestCheckResourceAttr("data.mastodon_account.test", "display_name", "Cool Dude"),
-					resource.TestCheckResourceAttr("data.mastodon_account.test", "created_at", "2019-06-09 00:00:00 +0000 UTC"),
-					resource.TestCheckResourceAttr("data.mastodon_account.test", "url", "https://example.com/user/tyr"),
+					resource.TestCheckResourceAttr("data.mastodon_account.test", "display_name", "Cool Dude :blobcat:"),
+					resource.TestCheckResourceAttr("data.mastodon_account.test", "created_at", account.CreatedAt.String()),
+					resource.TestCheckResourceAttr("data.mastodon_account.test", "url", account.URL),
 					resource.TestCheckResourceAttr("data.mastodon_account.test", "discoverable", "true"),
+					resource.TestCheckResourceAttr("data.mastodon_account.test", "note", "<p>Hello</p>"),
+					resource.TestCheckResourceAttr("data.mastodon_account.test", "avatar", account.Avatar),
+					resource.TestCheckResourceAttr("data.mastodon_account.test", "header_static", account.HeaderStatic),
+					resource.TestCheckResourceAttr("data.mastodon_account.test", "bot", "true"),
+					resource.TestCheckResourceAttr("data.mastodon_account.test", "locked", "true"),
+					resource.TestCheckResourceAttr("data.mastodon_account.test", "group", "false"),
+					resource.TestCheckResourceAttr("data.mastodon_account.test", "followers_count", "3"),
+					resource.TestCheckResourceAttr("data.mastodon_account.test", "following_count", "2"),
+					resource.TestCheckResourceAttr("data.mastodon_account.test", "statuses_count", "1"),
+					resource.TestCheckResourceAttr("data.mastodon_account.test", "last_status_at", "2022-11-06"),
+					resource.TestCheckResourceAttr("data.mastodon_account.test", "fields.#", "2"),
+					resource.TestCheckResourceAttr("data.mastodon_account.test", "fields.0.name", "Website"),
+					resource.TestCheckResourceAttr("data.mastodon_account.test", "fields.0.verified_at", "2022-11-05T12:00:00Z"),
+					resource.TestCheckNoResourceAttr("data.mastodon_account.test", "fields.1.verified_at"),
+					resource.TestCheckResourceAttr("data.mastodon_account.test", "emojis.#", "1"),
+					resource.TestCheckResourceAttr("data.mastodon_account.test", "emojis.0.shortcode", "blobcat"),
+					resource.TestCheckResourceAttr("data.mastodon_account.test", "roles.0.name", "Owner"),
+					resource.TestCheckResourceAttr("data.mastodon_account.test", "roles.0.color", "#ff0000"),
+					resource.TestCheckResourceAttr("data.mastodon_account.test", "moved.id", moved.ID),
+					resource.TestCheckResourceAttr("data.mastodon_account.test", "moved.acct", "newuser"),
 				),
 			},
+			{
+				Config:      testAccAccountDataSourceConfig(s, "404"),
+				ExpectError: regexp.MustCompile("Not Found"),
+			},
 		},
 	})
 }
 
-const testAccAccountDataSourceConfigTmplPre = `
-provider "mastodon" {
-	domain = %[1]q
-	use_https = false
+func TestAccAccountDataSource_Acct(t *testing.T) {
+	s := fakemastodon.New()
+	defer s.Close()
+
+	account := s.AddAccount("user")
+	s.AddRemoteAccount("friend", "remote.example")
+	token := s.AddAdminToken()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) + fmt.Sprintf(testAccAccountDataSourceAcctConfigTmpl, "@user@"+s.Domain(), false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mastodon_account.test", "id", account.ID),
+					resource.TestCheckResourceAttr("data.mastodon_account.test", "acct", "@user@"+s.Domain()),
+					resource.TestCheckResourceAttr("data.mastodon_account.test", "account", "user"),
+					resource.TestCheckNoResourceAttr("data.mastodon_account.test", "moved.id"),
+					resource.TestCheckNoResourceAttr("data.mastodon_account.test", "last_status_at"),
+				),
+			},
+			// remote accounts the server doesn't know yet are only found when resolving
+			{
+				Config:      testAccProviderConfig(s) + fmt.Sprintf(testAccAccountDataSourceAcctConfigTmpl, "friend@remote.example", false),
+				ExpectError: regexp.MustCompile("Account Not Found"),
+			},
+			{
+				Config: testAccProviderConfigWithToken(s, token) + fmt.Sprintf(testAccAccountDataSourceAcctConfigTmpl, "friend@remote.example", true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.mastodon_account.test", "id"),
+					resource.TestCheckResourceAttr("data.mastodon_account.test", "account", "friend@remote.example"),
+					resource.TestCheckResourceAttr("data.mastodon_account.test", "url", "https://remote.example/@friend"),
+				),
+			},
+			{
+				Config: testAccProviderConfig(s) + `
+data "mastodon_account" "test" {
+	id   = "1"
+	acct = "user"
 }
+`,
+				ExpectError: regexp.MustCompile("Exactly one of id or acct must be set"),
+			},
+		},
+	})
+}
+
+const testAccAccountDataSourceAcctConfigTmpl = `
+data "mastodon_account" "test" {
+	acct    = %[1]q
+	resolve = %[2]t
+}
+`
 
+const testAccAccountDataSourceConfigTmpl = `
 data "mastodon_account" "test" {
-	id = "1"
+	id = %[1]q
 }
 `
 
-func testAccAccountDataSourceConfig(tsURL string) string {
-	return fmt.Sprintf(testAccAccountDataSourceConfigTmplPre, strings.TrimPrefix(tsURL, "http://"))
+func testAccAccountDataSourceConfig(s *fakemastodon.Server, id string) string {
+	return testAccProviderConfig(s) + fmt.Sprintf(testAccAccountDataSourceConfigTmpl, id)
 }
@@ -0,0 +1,45 @@
+package provider
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// accountImage is an image file to upload as an account's avatar or header.
+type accountImage struct {
+	name        string
+	content     []byte
+	contentType string
+	sha256      string
+}
+
+// readAccountImage reads the image file at path. The content type is detected from the content, like the
+// server does, falling back to the extension for formats Go doesn't detect.
+func readAccountImage(path string) (*accountImage, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := http.DetectContentType(content)
+	if contentType == "application/octet-stream" {
+		if mediaType, _, err := mime.ParseMediaType(mime.TypeByExtension(filepath.Ext(path))); err == nil {
+			contentType = mediaType
+		}
+	}
+	contentType, _, _ = strings.Cut(contentType, ";")
+
+	sum := sha256.Sum256(content)
+
+	return &accountImage{
+		name:        filepath.Base(path),
+		content:     content,
+		contentType: contentType,
+		sha256:      hex.EncodeToString(sum[:]),
+	}, nil
+}
@@ -0,0 +1,276 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ provider.ResourceType = accountPreferencesResourceType{}
+var _ resource.Resource = accountPreferencesResource{}
+var _ resource.ResourceWithImportState = accountPreferencesResource{}
+
+const (
+	privacyPublic   = "public"
+	privacyUnlisted = "unlisted"
+	privacyPrivate  = "private"
+)
+
+type accountPreferencesResourceType struct{}
+
+func (t accountPreferencesResourceType) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Account Preferences. Manages the defaults for new posts of an account, requires an access token belonging to it with the `read:accounts` and `write:accounts` scopes. Attributes that aren't configured are left as they are. Destroying the resource doesn't change the preferences.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			// inputs
+			"access_token": {
+				MarkdownDescription: "Access token of the account. Defaults to the provider's `access_token`, set it to manage several accounts with one provider.",
+				Optional:            true,
+				Sensitive:           true,
+				Type:                types.StringType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.RequiresReplace(),
+				},
+			},
+			"language": {
+				MarkdownDescription: "Default language of new posts as an ISO 639 code, such as `en`, or an empty string for none",
+				Optional:            true,
+				Computed:            true,
+				Type:                types.StringType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
+			},
+			"privacy": {
+				MarkdownDescription: "Default visibility of new posts, one of `public`, `unlisted` or `private`",
+				Optional:            true,
+				Computed:            true,
+				Type:                types.StringType,
+				Validators: []tfsdk.AttributeValidator{
+					stringOneOf(privacyPublic, privacyUnlisted, privacyPrivate),
+				},
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
+			},
+			"sensitive": {
+				MarkdownDescription: "Whether media in new posts is marked as sensitive by default",
+				Optional:            true,
+				Computed:            true,
+				Type:                types.BoolType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
+			},
+
+			// outputs
+			"id": {
+				MarkdownDescription: "identifier of the account",
+				Type:                types.StringType,
+				Computed:            true,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
+			},
+		},
+	}, nil
+}
+
+func (t accountPreferencesResourceType) NewResource(_ context.Context, in provider.Provider) (resource.Resource, diag.Diagnostics) {
+	prov, diags := convertProviderType(in)
+
+	return accountPreferencesResource{
+		provider: prov,
+	}, diags
+}
+
+type accountPreferencesResourceData struct {
+	AccessToken types.String `tfsdk:"access_token"`
+	Language    types.String `tfsdk:"language"`
+	Privacy     types.String `tfsdk:"privacy"`
+	Sensitive   types.Bool   `tfsdk:"sensitive"`
+
+	ID types.String `tfsdk:"id"`
+}
+
+// accountPreferences are the preferences of an account as returned by the preferences endpoint, which
+// names them differently than the account's source.
+type accountPreferences struct {
+	Visibility string  `json:"posting:default:visibility"`
+	Sensitive  bool    `json:"posting:default:sensitive"`
+	Language   *string `json:"posting:default:language"`
+}
+
+// params returns the request parameters setting the configured attributes.
+func (d accountPreferencesResourceData) params() url.Values {
+	params := url.Values{}
+
+	if !d.Language.IsNull() && !d.Language.IsUnknown() {
+		params.Set("source[language]", d.Language.Value)
+	}
+	if !d.Privacy.IsNull() && !d.Privacy.IsUnknown() {
+		params.Set("source[privacy]", d.Privacy.Value)
+	}
+	if !d.Sensitive.IsNull() && !d.Sensitive.IsUnknown() {
+		params.Set("source[sensitive]", strconv.FormatBool(d.Sensitive.Value))
+	}
+
+	return params
+}
+
+// update sets the data to the preferences. A missing language is an empty string, so it can be configured.
+func (d *accountPreferencesResourceData) update(privacy string, sensitive bool, language *string) {
+	d.Language = types.String{Value: ""}
+	if language != nil {
+		d.Language = types.String{Value: *language}
+	}
+	d.Privacy = types.String{Value: privacy}
+	d.Sensitive = types.Bool{Value: sensitive}
+}
+
+// verifyApplied adds an error for every configured attribute the server didn't set to the configured
+// value, so it's reported instead of showing up as a change on every plan.
+func (d accountPreferencesResourceData) verifyApplied(got accountPreferencesResourceData, diags *diag.Diagnostics) {
+	for name, values := range map[string][2]attr.Value{
+		"language":  {d.Language, got.Language},
+		"privacy":   {d.Privacy, got.Privacy},
+		"sensitive": {d.Sensitive, got.Sensitive},
+	} {
+		want, have := values[0], values[1]
+		if want.IsNull() || want.IsUnknown() || want.Equal(have) {
+			continue
+		}
+
+		diags.AddAttributeError(
+			path.Root(name),
+			"Preferences Not Updated",
+			fmt.Sprintf("The server returned %s instead of the configured value, it may have normalized the value.", have),
+		)
+	}
+}
+
+type accountPreferencesResource struct {
+	provider mastodonProvider
+}
+
+// apply updates the preferences to the configured attributes and sets the state to the result.
+func (r accountPreferencesResource) apply(ctx context.Context, config tfsdk.Config, state *tfsdk.State) diag.Diagnostics {
+	var data accountPreferencesResourceData
+
+	diags := config.Get(ctx, &data)
+	if diags.HasError() {
+		return diags
+	}
+
+	client, clientDiags := r.provider.newUserClient(ctx, data.AccessToken.Value)
+	diags.Append(clientDiags...)
+
+	if diags.HasError() {
+		return diags
+	}
+
+	var acc credentialAccount
+	_, err := doAPI(ctx, client, http.MethodPatch, "/api/v1/accounts/update_credentials", data.params(), &acc)
+	if err != nil {
+		addAPIError(&diags, "update preferences", err)
+
+		return diags
+	}
+
+	configured := data
+	data.update(acc.Source.Privacy, acc.Source.Sensitive, acc.Source.Language)
+	data.ID = types.String{Value: string(acc.ID)}
+
+	// the preferences were changed either way, so the server's values are saved before reporting what
+	// it normalized
+	diags.Append(state.Set(ctx, &data)...)
+	configured.verifyApplied(data, &diags)
+
+	return diags
+}
+
+func (r accountPreferencesResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	resp.Diagnostics.Append(r.apply(ctx, req.Config, &resp.State)...)
+
+	tflog.Trace(ctx, "created a resource")
+}
+
+func (r accountPreferencesResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data accountPreferencesResourceData
+
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.provider.newUserClient(ctx, data.AccessToken.Value)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var prefs accountPreferences
+	_, err := doAPI(ctx, client, http.MethodGet, "/api/v1/preferences", nil, &prefs)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "read preferences", err)
+
+		return
+	}
+
+	data.update(prefs.Visibility, prefs.Sensitive, prefs.Language)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r accountPreferencesResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	resp.Diagnostics.Append(r.apply(ctx, req.Config, &resp.State)...)
+}
+
+func (r accountPreferencesResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// the account remains, so its preferences are left as they are
+}
+
+func (r accountPreferencesResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	client, diags := r.provider.newUserClient(ctx, "")
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// imported preferences are managed with the provider's access token, so they must be its account's
+	var acc credentialAccount
+	_, err := doAPI(ctx, client, http.MethodGet, "/api/v1/accounts/verify_credentials", nil, &acc)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "read account", err)
+
+		return
+	}
+
+	if string(acc.ID) != req.ID {
+		resp.Diagnostics.AddError(
+			"Invalid Import ID",
+			fmt.Sprintf("The provider's access token belongs to account %s, preferences can only be imported for that account.", acc.ID),
+		)
+
+		return
+	}
+
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
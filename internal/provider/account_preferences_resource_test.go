@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/feditools/terraform-provider-mastodon/internal/fakemastodon"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccAccountPreferencesResource(t *testing.T) {
+	s := fakemastodon.New()
+	defer s.Close()
+
+	app := s.AddApp("terraform", "read write")
+	announcements := s.AddAccount("announcements")
+	announcementsToken := s.AddToken(app.ClientID, announcements.ID, "read", "write")
+	bot := s.AddAccount("bot")
+	botToken := s.AddToken(app.ClientID, bot.ID, "read:accounts", "write:accounts")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing, with the provider's and an explicit access token
+			{
+				Config: testAccAccountPreferencesResourceConfig(s, announcementsToken, botToken, `
+	privacy   = "unlisted"
+	language  = "en"
+	sensitive = true
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("mastodon_account_preferences.provider", "id", announcements.ID),
+					resource.TestCheckResourceAttr("mastodon_account_preferences.provider", "privacy", "unlisted"),
+					resource.TestCheckResourceAttr("mastodon_account_preferences.provider", "language", "en"),
+					resource.TestCheckResourceAttr("mastodon_account_preferences.provider", "sensitive", "true"),
+					resource.TestCheckResourceAttr("mastodon_account_preferences.explicit", "id", bot.ID),
+					resource.TestCheckResourceAttr("mastodon_account_preferences.explicit", "privacy", "unlisted"),
+					func(_ *terraform.State) error {
+						for _, id := range []string{announcements.ID, bot.ID} {
+							a := s.Account(id)
+							if a.Source.Privacy != "unlisted" || !a.Source.Sensitive || a.Source.Language == nil || *a.Source.Language != "en" {
+								return fmt.Errorf("unexpected preferences of account %s: %+v", id, a.Source)
+							}
+						}
+
+						return nil
+					},
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "mastodon_account_preferences.provider",
+				ImportState:       true,
+				ImportStateId:     announcements.ID,
+				ImportStateVerify: true,
+			},
+			// ImportState testing, another account than the provider's
+			{
+				ResourceName:  "mastodon_account_preferences.provider",
+				ImportState:   true,
+				ImportStateId: bot.ID,
+				ExpectError:   regexp.MustCompile("preferences can only be imported for that account"),
+			},
+			// Update and Read testing, unconfigured attributes are left as they are
+			{
+				Config: testAccAccountPreferencesResourceConfig(s, announcementsToken, botToken, `
+	privacy  = "private"
+	language = ""
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("mastodon_account_preferences.provider", "privacy", "private"),
+					resource.TestCheckResourceAttr("mastodon_account_preferences.provider", "language", ""),
+					resource.TestCheckResourceAttr("mastodon_account_preferences.provider", "sensitive", "true"),
+					func(_ *terraform.State) error {
+						if a := s.Account(bot.ID); a.Source.Language != nil {
+							return fmt.Errorf("expected language to be removed, got: %s", *a.Source.Language)
+						}
+
+						return nil
+					},
+				),
+			},
+			// Drift testing, preferences changed outside of terraform
+			{
+				PreConfig: func() {
+					s.UpdateAccount(bot.ID, func(a *fakemastodon.Account) {
+						a.Source.Privacy = "public"
+					})
+				},
+				Config: testAccAccountPreferencesResourceConfig(s, announcementsToken, botToken, `
+	privacy  = "private"
+	language = ""
+`),
+				Check: func(_ *terraform.State) error {
+					if a := s.Account(bot.ID); a.Source.Privacy != "private" {
+						return fmt.Errorf("expected privacy to be restored, got: %s", a.Source.Privacy)
+					}
+
+					return nil
+				},
+			},
+			// Direct visibility can't be a default
+			{
+				Config: testAccAccountPreferencesResourceConfig(s, announcementsToken, botToken, `
+	privacy = "direct"
+`),
+				ExpectError: regexp.MustCompile("Invalid Attribute Value"),
+			},
+		},
+	})
+}
+
+const testAccAccountPreferencesResourceConfigTmpl = `
+resource "mastodon_account_preferences" "provider" {
+%[2]s}
+
+resource "mastodon_account_preferences" "explicit" {
+	access_token = %[1]q
+%[2]s}
+`
+
+func testAccAccountPreferencesResourceConfig(s *fakemastodon.Server, providerToken, resourceToken, attributes string) string {
+	return testAccProviderConfigWithToken(s, providerToken) + fmt.Sprintf(testAccAccountPreferencesResourceConfigTmpl, resourceToken, attributes)
+}
@@ -0,0 +1,568 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ provider.ResourceType = accountProfileResourceType{}
+var _ resource.Resource = accountProfileResource{}
+var _ resource.ResourceWithModifyPlan = accountProfileResource{}
+var _ resource.ResourceWithImportState = accountProfileResource{}
+
+// accountProfileFieldTypes are the attribute types of a configured profile field object.
+var accountProfileFieldTypes = map[string]attr.Type{
+	"name":  types.StringType,
+	"value": types.StringType,
+}
+
+type accountProfileResourceType struct{}
+
+func (t accountProfileResourceType) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Account Profile. Manages the profile of the account the access token belongs to, requires the `read:accounts` and `write:accounts` scopes. Attributes that aren't configured are left as they are. Destroying the resource doesn't change the profile.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			// inputs
+			"avatar": {
+				MarkdownDescription: "Path of an image file to upload as the avatar. It's only uploaded again when the file's content changes. Removing the attribute leaves the avatar as it is.",
+				Optional:            true,
+				Type:                types.StringType,
+			},
+			"bot": {
+				MarkdownDescription: "Whether the account is automated",
+				Optional:            true,
+				Computed:            true,
+				Type:                types.BoolType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
+			},
+			"discoverable": {
+				MarkdownDescription: "Whether the account is featured in the profile directory",
+				Optional:            true,
+				Computed:            true,
+				Type:                types.BoolType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
+			},
+			"display_name": {
+				MarkdownDescription: "Display name",
+				Optional:            true,
+				Computed:            true,
+				Type:                types.StringType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
+			},
+			"fields": {
+				MarkdownDescription: "Profile fields, at most as many as the server allows, which is 4 for Mastodon",
+				Optional:            true,
+				Computed:            true,
+				Attributes: tfsdk.ListNestedAttributes(map[string]tfsdk.Attribute{
+					"name": {
+						MarkdownDescription: "Label of the field",
+						Required:            true,
+						Type:                types.StringType,
+					},
+					"value": {
+						MarkdownDescription: "Value of the field, as plain text",
+						Required:            true,
+						Type:                types.StringType,
+					},
+				}),
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
+			},
+			"header": {
+				MarkdownDescription: "Path of an image file to upload as the header. It's only uploaded again when the file's content changes. Removing the attribute leaves the header as it is.",
+				Optional:            true,
+				Type:                types.StringType,
+			},
+			"hide_collections": {
+				MarkdownDescription: "Whether the accounts followed and following the account are hidden",
+				Optional:            true,
+				Computed:            true,
+				Type:                types.BoolType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
+			},
+			"indexable": {
+				MarkdownDescription: "Whether public posts of the account can be found by searching, requires Mastodon 4.2 or later",
+				Optional:            true,
+				Computed:            true,
+				Type:                types.BoolType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
+			},
+			"locked": {
+				MarkdownDescription: "Whether follow requests are reviewed manually",
+				Optional:            true,
+				Computed:            true,
+				Type:                types.BoolType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
+			},
+			"note": {
+				MarkdownDescription: "Profile bio, as plain text",
+				Optional:            true,
+				Computed:            true,
+				Type:                types.StringType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
+			},
+
+			// outputs
+			"acct": {
+				MarkdownDescription: "Account handle",
+				Type:                types.StringType,
+				Computed:            true,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
+			},
+			"avatar_sha256": {
+				MarkdownDescription: "SHA-256 hash of the uploaded `avatar` file, null if `avatar` isn't set",
+				Type:                types.StringType,
+				Computed:            true,
+			},
+			"avatar_url": {
+				MarkdownDescription: "Avatar image URL",
+				Type:                types.StringType,
+				Computed:            true,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
+			},
+			"header_sha256": {
+				MarkdownDescription: "SHA-256 hash of the uploaded `header` file, null if `header` isn't set",
+				Type:                types.StringType,
+				Computed:            true,
+			},
+			"header_url": {
+				MarkdownDescription: "Header image URL",
+				Type:                types.StringType,
+				Computed:            true,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
+			},
+			"id": {
+				MarkdownDescription: "identifier of the account",
+				Type:                types.StringType,
+				Computed:            true,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
+			},
+			"url": {
+				MarkdownDescription: "Profile page",
+				Type:                types.StringType,
+				Computed:            true,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
+			},
+		},
+	}, nil
+}
+
+func (t accountProfileResourceType) NewResource(_ context.Context, in provider.Provider) (resource.Resource, diag.Diagnostics) {
+	prov, diags := convertProviderType(in)
+
+	return accountProfileResource{
+		provider: prov,
+	}, diags
+}
+
+type accountProfileResourceData struct {
+	Avatar          types.String `tfsdk:"avatar"`
+	Bot             types.Bool   `tfsdk:"bot"`
+	Discoverable    types.Bool   `tfsdk:"discoverable"`
+	DisplayName     types.String `tfsdk:"display_name"`
+	Fields          types.List   `tfsdk:"fields"`
+	Header          types.String `tfsdk:"header"`
+	HideCollections types.Bool   `tfsdk:"hide_collections"`
+	Indexable       types.Bool   `tfsdk:"indexable"`
+	Locked          types.Bool   `tfsdk:"locked"`
+	Note            types.String `tfsdk:"note"`
+
+	Acct         types.String `tfsdk:"acct"`
+	AvatarSHA256 types.String `tfsdk:"avatar_sha256"`
+	AvatarURL    types.String `tfsdk:"avatar_url"`
+	HeaderSHA256 types.String `tfsdk:"header_sha256"`
+	HeaderURL    types.String `tfsdk:"header_url"`
+	ID           types.String `tfsdk:"id"`
+	URL          types.String `tfsdk:"url"`
+}
+
+// accountProfileImage is an uploaded image of the profile, pointing to the attributes describing it.
+type accountProfileImage struct {
+	name   string
+	path   *types.String
+	sha256 *types.String
+	url    *types.String
+}
+
+// images returns the avatar and the header, in that order.
+func (d *accountProfileResourceData) images() []accountProfileImage {
+	return []accountProfileImage{
+		{name: "avatar", path: &d.Avatar, sha256: &d.AvatarSHA256, url: &d.AvatarURL},
+		{name: "header", path: &d.Header, sha256: &d.HeaderSHA256, url: &d.HeaderURL},
+	}
+}
+
+// params returns the request parameters setting the configured attributes. Profile fields replace all
+// existing fields, so an empty list is sent as a single blank field to remove them.
+func (d accountProfileResourceData) params() url.Values {
+	params := url.Values{}
+
+	for name, value := range map[string]types.Bool{
+		"bot":              d.Bot,
+		"discoverable":     d.Discoverable,
+		"hide_collections": d.HideCollections,
+		"indexable":        d.Indexable,
+		"locked":           d.Locked,
+	} {
+		if !value.IsNull() && !value.IsUnknown() {
+			params.Set(name, strconv.FormatBool(value.Value))
+		}
+	}
+
+	if !d.DisplayName.IsNull() && !d.DisplayName.IsUnknown() {
+		params.Set("display_name", d.DisplayName.Value)
+	}
+	if !d.Note.IsNull() && !d.Note.IsUnknown() {
+		params.Set("note", d.Note.Value)
+	}
+
+	if !d.Fields.IsNull() && !d.Fields.IsUnknown() {
+		for i, elem := range d.Fields.Elems {
+			obj, ok := elem.(types.Object)
+			if !ok {
+				continue
+			}
+
+			name, _ := obj.Attrs["name"].(types.String)
+			value, _ := obj.Attrs["value"].(types.String)
+			params.Set(fmt.Sprintf("fields_attributes[%d][name]", i), name.Value)
+			params.Set(fmt.Sprintf("fields_attributes[%d][value]", i), value.Value)
+		}
+
+		if len(d.Fields.Elems) == 0 {
+			params.Set("fields_attributes[0][name]", "")
+			params.Set("fields_attributes[0][value]", "")
+		}
+	}
+
+	return params
+}
+
+// update sets the data to the values of acc, using its source so the plain text profile is compared
+// with the configuration.
+func (d *accountProfileResourceData) update(acc *credentialAccount) {
+	orDefault := func(b *bool, def bool) types.Bool {
+		if b == nil {
+			return types.Bool{Value: def}
+		}
+
+		return types.Bool{Value: *b}
+	}
+
+	d.Bot = types.Bool{Value: acc.Bot}
+	d.Discoverable = orDefault(acc.Source.Discoverable, acc.Discoverable)
+	d.DisplayName = types.String{Value: acc.DisplayName}
+	d.HideCollections = orDefault(acc.Source.HideCollections, acc.HideCollections)
+	d.Indexable = orDefault(acc.Source.Indexable, acc.Indexable)
+	d.Locked = types.Bool{Value: acc.Locked}
+	d.Note = types.String{Value: acc.Source.Note}
+
+	d.Fields = types.List{
+		ElemType: types.ObjectType{AttrTypes: accountProfileFieldTypes},
+		Elems:    make([]attr.Value, 0, len(acc.Source.Fields)),
+	}
+	for _, field := range acc.Source.Fields {
+		d.Fields.Elems = append(d.Fields.Elems, types.Object{
+			AttrTypes: accountProfileFieldTypes,
+			Attrs: map[string]attr.Value{
+				"name":  types.String{Value: field.Name},
+				"value": types.String{Value: field.Value},
+			},
+		})
+	}
+
+	d.Acct = types.String{Value: acc.Acct}
+	d.AvatarURL = types.String{Value: acc.Avatar}
+	d.HeaderURL = types.String{Value: acc.Header}
+	d.ID = types.String{Value: string(acc.ID)}
+	d.URL = types.String{Value: acc.URL}
+}
+
+// verifyApplied adds an error for every configured attribute the server didn't set to the configured
+// value, usually because it doesn't support it, so it's reported instead of showing up as a change on
+// every plan.
+func (d accountProfileResourceData) verifyApplied(got accountProfileResourceData, diags *diag.Diagnostics) {
+	for name, values := range map[string][2]attr.Value{
+		"bot":              {d.Bot, got.Bot},
+		"discoverable":     {d.Discoverable, got.Discoverable},
+		"display_name":     {d.DisplayName, got.DisplayName},
+		"fields":           {d.Fields, got.Fields},
+		"hide_collections": {d.HideCollections, got.HideCollections},
+		"indexable":        {d.Indexable, got.Indexable},
+		"locked":           {d.Locked, got.Locked},
+		"note":             {d.Note, got.Note},
+	} {
+		want, have := values[0], values[1]
+		if want.IsNull() || want.IsUnknown() || want.Equal(have) {
+			continue
+		}
+
+		diags.AddAttributeError(
+			path.Root(name),
+			"Profile Not Updated",
+			fmt.Sprintf("The server returned %s instead of the configured value, it may not support the attribute or may have changed the value.", have),
+		)
+	}
+}
+
+type accountProfileResource struct {
+	provider mastodonProvider
+}
+
+func (r accountProfileResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// nothing to check when destroying
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan, prior accountProfileResourceData
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+
+	if !req.State.Raw.IsNull() {
+		diags = req.State.Get(ctx, &prior)
+		resp.Diagnostics.Append(diags...)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// images are identified by their content, so only a changed file is uploaded again
+	var uploads []accountProfileImage
+	var images []*accountImage
+	priorImages := prior.images()
+	for i, image := range plan.images() {
+		switch {
+		case image.path.IsUnknown():
+			*image.sha256 = types.String{Unknown: true}
+			*image.url = types.String{Unknown: true}
+		case image.path.IsNull():
+			*image.sha256 = types.String{Null: true}
+		default:
+			img, err := readAccountImage(image.path.Value)
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root(image.name), "Unable to Read Image", err.Error())
+
+				continue
+			}
+
+			*image.sha256 = types.String{Value: img.sha256}
+			if img.sha256 != priorImages[i].sha256.Value {
+				*image.url = types.String{Unknown: true}
+				uploads = append(uploads, image)
+				images = append(images, img)
+			}
+		}
+	}
+
+	diags = resp.Plan.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+
+	checkFields := !plan.Fields.IsNull() && !plan.Fields.IsUnknown() && len(plan.Fields.Elems) > 0
+	if resp.Diagnostics.HasError() || (!checkFields && len(uploads) == 0) {
+		return
+	}
+
+	// fail at plan time rather than halfway through an apply
+	config, err := getInstanceConfiguration(ctx, r.provider.newClient())
+	if err != nil {
+		tflog.Debug(ctx, "unable to read instance configuration", map[string]interface{}{"error": err.Error()})
+
+		return
+	}
+
+	if maxFields := config.maxProfileFields(); checkFields && len(plan.Fields.Elems) > maxFields {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("fields"),
+			"Too Many Profile Fields",
+			fmt.Sprintf("The server allows at most %d profile fields, got %d.", maxFields, len(plan.Fields.Elems)),
+		)
+	}
+
+	for i, image := range uploads {
+		if err := config.checkImage(images[i].contentType, int64(len(images[i].content))); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(image.name),
+				"Unsupported Image",
+				fmt.Sprintf("Unable to upload %s as the %s, %s.", image.path.Value, image.name, err),
+			)
+		}
+	}
+}
+
+// apply updates the profile to the configured attributes and sets the state to the result. Only the
+// configured attributes are sent, the others are left as they are. Images are uploaded if their content
+// differs from the one in prior.
+func (r accountProfileResource) apply(ctx context.Context, config tfsdk.Config, prior accountProfileResourceData, state *tfsdk.State) diag.Diagnostics {
+	var data accountProfileResourceData
+
+	diags := config.Get(ctx, &data)
+	if diags.HasError() {
+		return diags
+	}
+
+	var files []apiFile
+	priorImages := prior.images()
+	for i, image := range data.images() {
+		if image.path.IsNull() {
+			continue
+		}
+
+		img, err := readAccountImage(image.path.Value)
+		if err != nil {
+			diags.AddAttributeError(path.Root(image.name), "Unable to Read Image", err.Error())
+
+			return diags
+		}
+
+		*image.sha256 = types.String{Value: img.sha256}
+		if img.sha256 != priorImages[i].sha256.Value {
+			files = append(files, apiFile{field: image.name, name: img.name, content: img.content})
+		}
+	}
+
+	client, clientDiags := r.provider.newUserClient(ctx, "")
+	diags.Append(clientDiags...)
+
+	if diags.HasError() {
+		return diags
+	}
+
+	var acc credentialAccount
+	var err error
+	if len(files) > 0 {
+		_, err = doMultipartAPI(ctx, client, http.MethodPatch, "/api/v1/accounts/update_credentials", data.params(), files, &acc)
+	} else {
+		_, err = doAPI(ctx, client, http.MethodPatch, "/api/v1/accounts/update_credentials", data.params(), &acc)
+	}
+	if err != nil {
+		addAPIError(&diags, "update profile", err)
+
+		return diags
+	}
+
+	configured := data
+	data.update(&acc)
+
+	// the profile was changed either way, so the server's values are saved before reporting what it
+	// ignored
+	diags.Append(state.Set(ctx, &data)...)
+	configured.verifyApplied(data, &diags)
+
+	return diags
+}
+
+func (r accountProfileResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	resp.Diagnostics.Append(r.apply(ctx, req.Config, accountProfileResourceData{}, &resp.State)...)
+
+	tflog.Trace(ctx, "created a resource")
+}
+
+func (r accountProfileResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data accountProfileResourceData
+
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.provider.newUserClient(ctx, "")
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var acc credentialAccount
+	_, err := doAPI(ctx, client, http.MethodGet, "/api/v1/accounts/verify_credentials", nil, &acc)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "read profile", err)
+
+		return
+	}
+
+	if data.ID.Value != "" && data.ID.Value != string(acc.ID) {
+		resp.Diagnostics.AddError(
+			"Account Mismatch",
+			fmt.Sprintf("The access token belongs to account %s (%s), not account %s.", acc.ID, acc.Acct, data.ID.Value),
+		)
+
+		return
+	}
+
+	// an image replaced outside of terraform is no longer the uploaded file, so it's uploaded again
+	if data.AvatarURL.Value != acc.Avatar {
+		data.AvatarSHA256 = types.String{Null: true}
+	}
+	if data.HeaderURL.Value != acc.Header {
+		data.HeaderSHA256 = types.String{Null: true}
+	}
+
+	data.update(&acc)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r accountProfileResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var prior accountProfileResourceData
+
+	diags := req.State.Get(ctx, &prior)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.apply(ctx, req.Config, prior, &resp.State)...)
+}
+
+func (r accountProfileResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// the account remains, so its profile is left as it is
+}
+
+func (r accountProfileResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
@@ -0,0 +1,269 @@
+package provider
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/feditools/terraform-provider-mastodon/internal/fakemastodon"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccAccountProfileResource(t *testing.T) {
+	s := fakemastodon.New()
+	defer s.Close()
+
+	account := s.AddAccount("announcements")
+	app := s.AddApp("terraform", "read write")
+	token := s.AddToken(app.ClientID, account.ID, "read", "write")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccAccountProfileResourceConfig(s, token, `
+	display_name = "Announcements"
+	note         = "Announcements about this server.\nNot monitored."
+	bot          = true
+	fields = [
+		{ name = "Operator", value = "admin@example.com" },
+		{ name = "Source", value = "https://example.com/source" },
+	]
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("mastodon_account_profile.test", "id", account.ID),
+					resource.TestCheckResourceAttr("mastodon_account_profile.test", "acct", "announcements"),
+					resource.TestCheckResourceAttr("mastodon_account_profile.test", "display_name", "Announcements"),
+					resource.TestCheckResourceAttr("mastodon_account_profile.test", "bot", "true"),
+					resource.TestCheckResourceAttr("mastodon_account_profile.test", "locked", "false"),
+					resource.TestCheckResourceAttr("mastodon_account_profile.test", "fields.#", "2"),
+					resource.TestCheckResourceAttr("mastodon_account_profile.test", "fields.1.value", "https://example.com/source"),
+					func(_ *terraform.State) error {
+						a := s.Account(account.ID)
+						if a.Note != "<p>Announcements about this server.<br />Not monitored.</p>" {
+							return fmt.Errorf("unexpected note: %s", a.Note)
+						}
+
+						return nil
+					},
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "mastodon_account_profile.test",
+				ImportState:       true,
+				ImportStateId:     account.ID,
+				ImportStateVerify: true,
+			},
+			// Update and Read testing, unconfigured attributes are left as they are
+			{
+				Config: testAccAccountProfileResourceConfig(s, token, `
+	display_name = "Server News"
+	fields       = []
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("mastodon_account_profile.test", "display_name", "Server News"),
+					resource.TestCheckResourceAttr("mastodon_account_profile.test", "bot", "true"),
+					resource.TestCheckResourceAttr("mastodon_account_profile.test", "note", "Announcements about this server.\nNot monitored."),
+					resource.TestCheckResourceAttr("mastodon_account_profile.test", "fields.#", "0"),
+				),
+			},
+			// Drift testing, profile changed outside of terraform
+			{
+				PreConfig: func() {
+					s.UpdateAccount(account.ID, func(a *fakemastodon.Account) {
+						a.DisplayName = "Edited"
+						a.Bot = false
+					})
+				},
+				Config: testAccAccountProfileResourceConfig(s, token, `
+	display_name = "Server News"
+	fields       = []
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("mastodon_account_profile.test", "display_name", "Server News"),
+					resource.TestCheckResourceAttr("mastodon_account_profile.test", "bot", "false"),
+					func(_ *terraform.State) error {
+						if a := s.Account(account.ID); a.DisplayName != "Server News" {
+							return fmt.Errorf("expected display name to be restored, got: %s", a.DisplayName)
+						}
+
+						return nil
+					},
+				),
+			},
+			// Mastodon allows 4 profile fields
+			{
+				Config: testAccAccountProfileResourceConfig(s, token, `
+	fields = [
+		{ name = "1", value = "1" },
+		{ name = "2", value = "2" },
+		{ name = "3", value = "3" },
+		{ name = "4", value = "4" },
+		{ name = "5", value = "5" },
+	]
+`),
+				ExpectError: regexp.MustCompile("The server allows at most 4 profile fields, got 5"),
+			},
+		},
+	})
+}
+
+func TestAccAccountProfileResource_images(t *testing.T) {
+	s := fakemastodon.New()
+	defer s.Close()
+	s.Instance.Configuration.MediaAttachments.ImageSizeLimit = 64 * 1024
+
+	account := s.AddAccount("announcements")
+	app := s.AddApp("terraform", "read write")
+	token := s.AddToken(app.ClientID, account.ID, "read", "write")
+
+	dir := t.TempDir()
+	red := testAccWriteImage(t, filepath.Join(dir, "red.png"), color.RGBA{R: 255, A: 255})
+	testAccWriteImage(t, filepath.Join(dir, "copy.png"), color.RGBA{R: 255, A: 255})
+
+	// the server only checks the content, so a PNG signature followed by padding is a large PNG image
+	err := os.WriteFile(filepath.Join(dir, "large.png"), append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 128*1024)...), 0o600)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	err = os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not an image"), 0o600)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var avatarURL string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccAccountProfileResourceConfig(s, token, fmt.Sprintf(`
+	avatar = %q
+`, filepath.Join(dir, "red.png"))),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("mastodon_account_profile.test", "avatar_sha256", red),
+					resource.TestCheckNoResourceAttr("mastodon_account_profile.test", "header_sha256"),
+					resource.TestCheckResourceAttr("mastodon_account_profile.test", "header_url", s.URL+"/headers/original/missing.png"),
+					func(_ *terraform.State) error {
+						avatarURL = s.Account(account.ID).Avatar
+						if !strings.HasSuffix(avatarURL, ".png") || strings.Contains(avatarURL, "missing") {
+							return fmt.Errorf("expected avatar to be uploaded, got: %s", avatarURL)
+						}
+
+						return nil
+					},
+					resource.TestCheckResourceAttrPtr("mastodon_account_profile.test", "avatar_url", &avatarURL),
+				),
+			},
+			// Moving the file doesn't upload it again
+			{
+				Config: testAccAccountProfileResourceConfig(s, token, fmt.Sprintf(`
+	avatar = %q
+`, filepath.Join(dir, "copy.png"))),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("mastodon_account_profile.test", "avatar_sha256", red),
+					resource.TestCheckResourceAttrPtr("mastodon_account_profile.test", "avatar_url", &avatarURL),
+				),
+			},
+			// Changing the file's content uploads it again
+			{
+				PreConfig: func() {
+					testAccWriteImage(t, filepath.Join(dir, "copy.png"), color.RGBA{B: 255, A: 255})
+				},
+				Config: testAccAccountProfileResourceConfig(s, token, fmt.Sprintf(`
+	avatar = %q
+`, filepath.Join(dir, "copy.png"))),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrWith("mastodon_account_profile.test", "avatar_sha256", func(value string) error {
+						if value == red {
+							return fmt.Errorf("expected hash of the changed file")
+						}
+
+						return nil
+					}),
+					func(_ *terraform.State) error {
+						if s.Account(account.ID).Avatar == avatarURL {
+							return fmt.Errorf("expected avatar to be uploaded again")
+						}
+
+						return nil
+					},
+				),
+			},
+			// Replacing the avatar outside of terraform uploads it again
+			{
+				PreConfig: func() {
+					s.UpdateAccount(account.ID, func(a *fakemastodon.Account) {
+						a.Avatar = s.URL + "/avatars/original/missing.png"
+					})
+				},
+				Config: testAccAccountProfileResourceConfig(s, token, fmt.Sprintf(`
+	avatar = %q
+`, filepath.Join(dir, "copy.png"))),
+				Check: func(_ *terraform.State) error {
+					if strings.Contains(s.Account(account.ID).Avatar, "missing") {
+						return fmt.Errorf("expected avatar to be uploaded again")
+					}
+
+					return nil
+				},
+			},
+			// Images are checked against the server's limits
+			{
+				Config: testAccAccountProfileResourceConfig(s, token, fmt.Sprintf(`
+	header = %q
+`, filepath.Join(dir, "large.png"))),
+				ExpectError: regexp.MustCompile("the server allows at most 65536 bytes"),
+			},
+			{
+				Config: testAccAccountProfileResourceConfig(s, token, fmt.Sprintf(`
+	header = %q
+`, filepath.Join(dir, "notes.txt"))),
+				ExpectError: regexp.MustCompile("not an image"),
+			},
+		},
+	})
+}
+
+// testAccWriteImage writes a PNG image of a single color to file and returns its SHA-256 hash.
+func testAccWriteImage(t *testing.T, file string, c color.Color) string {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	draw.Draw(img, img.Bounds(), image.NewUniform(c), image.Point{}, draw.Src)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := os.WriteFile(file, buf.Bytes(), 0o600); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+
+	return hex.EncodeToString(sum[:])
+}
+
+const testAccAccountProfileResourceConfigTmpl = `
+resource "mastodon_account_profile" "test" {
+%[1]s}
+`
+
+func testAccAccountProfileResourceConfig(s *fakemastodon.Server, token, attributes string) string {
+	return testAccProviderConfigWithToken(s, token) + fmt.Sprintf(testAccAccountProfileResourceConfigTmpl, attributes)
+}
@@ -0,0 +1,172 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"sort"
+	"strings"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ provider.DataSourceType = adminDomainBlocksDataSourceType{}
+var _ datasource.DataSource = adminDomainBlocksDataSource{}
+
+// adminDomainBlockTypes are the attribute types of a domain block object read from the server, which
+// also has its id.
+var adminDomainBlockTypes = func() map[string]attr.Type {
+	attrTypes := map[string]attr.Type{
+		"id": types.StringType,
+	}
+	for name, typ := range domainBlockTypes {
+		attrTypes[name] = typ
+	}
+
+	return attrTypes
+}()
+
+type adminDomainBlocksDataSourceType struct{}
+
+func (t adminDomainBlocksDataSourceType) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Domain blocks on the server, requires an access token with the `admin:read:domain_blocks` scope.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"domain_suffix": {
+				MarkdownDescription: "Only list blocks of this domain and its subdomains",
+				Type:                types.StringType,
+				Optional:            true,
+			},
+			"severity": {
+				MarkdownDescription: "Only list blocks with this severity, one of `noop`, `silence` or `suspend`",
+				Type:                types.StringType,
+				Optional:            true,
+				Validators: []tfsdk.AttributeValidator{
+					stringOneOf(domainBlockSeverityNoop, domainBlockSeveritySilence, domainBlockSeveritySuspend),
+				},
+			},
+
+			"blocks": {
+				MarkdownDescription: "Domain blocks, sorted by domain",
+				Type: types.ListType{
+					ElemType: types.ObjectType{
+						AttrTypes: adminDomainBlockTypes,
+					},
+				},
+				Computed: true,
+			},
+			"domains": {
+				MarkdownDescription: "Blocked domains, sorted",
+				Type: types.ListType{
+					ElemType: types.StringType,
+				},
+				Computed: true,
+			},
+			"id": {
+				MarkdownDescription: "identifier",
+				Type:                types.StringType,
+				Computed:            true,
+			},
+		},
+	}, nil
+}
+
+func (t adminDomainBlocksDataSourceType) NewDataSource(_ context.Context, in provider.Provider) (datasource.DataSource, diag.Diagnostics) {
+	prov, diags := convertProviderType(in)
+
+	return adminDomainBlocksDataSource{
+		provider: prov,
+	}, diags
+}
+
+type adminDomainBlocksDataSourceData struct {
+	DomainSuffix types.String `tfsdk:"domain_suffix"`
+	Severity     types.String `tfsdk:"severity"`
+
+	Blocks  types.List   `tfsdk:"blocks"`
+	Domains types.List   `tfsdk:"domains"`
+	ID      types.String `tfsdk:"id"`
+}
+
+// matches returns true if block passes the configured filters.
+func (d adminDomainBlocksDataSourceData) matches(block domainBlock) bool {
+	if !d.Severity.IsNull() && block.Severity != d.Severity.Value {
+		return false
+	}
+
+	if !d.DomainSuffix.IsNull() {
+		suffix := strings.TrimPrefix(strings.ToLower(d.DomainSuffix.Value), ".")
+		if block.Domain != suffix && !strings.HasSuffix(block.Domain, "."+suffix) {
+			return false
+		}
+	}
+
+	return true
+}
+
+type adminDomainBlocksDataSource struct {
+	provider mastodonProvider
+}
+
+func (d adminDomainBlocksDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data adminDomainBlocksDataSourceData
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(d.provider.requireCapability(capAdminDomainBlocks, "mastodon_admin_domain_blocks")...)
+
+	client, diags := d.provider.newAdminClient(ctx)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	blocks, err := listAll[domainBlock](ctx, client, "/api/v1/admin/domain_blocks", nil)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "list domain blocks", err)
+
+		return
+	}
+
+	// the server lists newest first, sort so adding a block doesn't reorder the others
+	sort.Slice(blocks, func(i, j int) bool {
+		return blocks[i].Domain < blocks[j].Domain
+	})
+
+	data.Blocks = types.List{
+		ElemType: types.ObjectType{AttrTypes: adminDomainBlockTypes},
+		Elems:    []attr.Value{},
+	}
+	data.Domains = types.List{
+		ElemType: types.StringType,
+		Elems:    []attr.Value{},
+	}
+	for _, block := range blocks {
+		if !data.matches(block) {
+			continue
+		}
+
+		obj := domainBlockObject(block)
+		obj.AttrTypes = adminDomainBlockTypes
+		obj.Attrs["id"] = types.String{Value: block.ID}
+
+		data.Blocks.Elems = append(data.Blocks.Elems, obj)
+		data.Domains.Elems = append(data.Domains.Elems, types.String{Value: block.Domain})
+	}
+
+	data.ID = types.String{Value: d.provider.domain}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/feditools/terraform-provider-mastodon/internal/fakemastodon"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccAdminDomainBlocksDataSource(t *testing.T) {
+	s := fakemastodon.New()
+	defer s.Close()
+
+	token := s.AddAdminToken()
+
+	// enough blocks to need more than one page
+	for i := 0; i < 150; i++ {
+		s.AddDomainBlock(fakemastodon.DomainBlock{Domain: fmt.Sprintf("spam%03d.example", i)})
+	}
+	s.AddDomainBlock(fakemastodon.DomainBlock{Domain: "bad.example", Severity: "suspend", RejectMedia: true})
+	s.AddDomainBlock(fakemastodon.DomainBlock{Domain: "sub.bad.example", Severity: "suspend"})
+	s.AddDomainBlock(fakemastodon.DomainBlock{Domain: "notbad.example", Severity: "suspend"})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAdminDomainBlocksDataSourceConfig(s, token, ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mastodon_admin_domain_blocks.test", "id", s.Domain()),
+					resource.TestCheckResourceAttr("data.mastodon_admin_domain_blocks.test", "blocks.#", "153"),
+					resource.TestCheckResourceAttr("data.mastodon_admin_domain_blocks.test", "domains.#", "153"),
+					resource.TestCheckResourceAttr("data.mastodon_admin_domain_blocks.test", "domains.0", "bad.example"),
+					resource.TestCheckResourceAttr("data.mastodon_admin_domain_blocks.test", "domains.152", "sub.bad.example"),
+					resource.TestCheckResourceAttrSet("data.mastodon_admin_domain_blocks.test", "blocks.0.id"),
+					resource.TestCheckResourceAttr("data.mastodon_admin_domain_blocks.test", "blocks.0.severity", "suspend"),
+					resource.TestCheckResourceAttr("data.mastodon_admin_domain_blocks.test", "blocks.0.reject_media", "true"),
+				),
+			},
+			{
+				Config: testAccAdminDomainBlocksDataSourceConfig(s, token, `
+	severity = "suspend"
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mastodon_admin_domain_blocks.test", "domains.#", "3"),
+				),
+			},
+			{
+				Config: testAccAdminDomainBlocksDataSourceConfig(s, token, `
+	domain_suffix = "bad.example"
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mastodon_admin_domain_blocks.test", "domains.#", "2"),
+					resource.TestCheckResourceAttr("data.mastodon_admin_domain_blocks.test", "domains.0", "bad.example"),
+					resource.TestCheckResourceAttr("data.mastodon_admin_domain_blocks.test", "domains.1", "sub.bad.example"),
+				),
+			},
+		},
+	})
+}
+
+const testAccAdminDomainBlocksDataSourceConfigTmpl = `
+data "mastodon_admin_domain_blocks" "test" {
+%[1]s}
+`
+
+func testAccAdminDomainBlocksDataSourceConfig(s *fakemastodon.Server, token, attributes string) string {
+	return testAccProviderConfigWithToken(s, token) + fmt.Sprintf(testAccAdminDomainBlocksDataSourceConfigTmpl, attributes)
+}
@@ -0,0 +1,286 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	blocklistFormatCSV  = "csv"
+	blocklistFormatJSON = "json"
+)
+
+// domainBlockTypes are the attribute types of a domain block object.
+var domainBlockTypes = map[string]attr.Type{
+	"domain":          types.StringType,
+	"obfuscate":       types.BoolType,
+	"private_comment": types.StringType,
+	"public_comment":  types.StringType,
+	"reject_media":    types.BoolType,
+	"reject_reports":  types.BoolType,
+	"severity":        types.StringType,
+}
+
+// domainBlockObject converts block to a domain block object.
+func domainBlockObject(block domainBlock) types.Object {
+	return types.Object{
+		AttrTypes: domainBlockTypes,
+		Attrs: map[string]attr.Value{
+			"domain":          types.String{Value: block.Domain},
+			"obfuscate":       types.Bool{Value: block.Obfuscate},
+			"private_comment": optionalString(block.PrivateComment),
+			"public_comment":  optionalString(block.PublicComment),
+			"reject_media":    types.Bool{Value: block.RejectMedia},
+			"reject_reports":  types.Bool{Value: block.RejectReports},
+			"severity":        types.String{Value: block.Severity},
+		},
+	}
+}
+
+// blocklistError is an invalid entry in a blocklist.
+type blocklistError struct {
+	Line int
+	Err  error
+}
+
+func (e *blocklistError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Err)
+}
+
+// blocklistCSVColumns are the columns of Mastodon's domain block export, used when a CSV file has no
+// header.
+var blocklistCSVColumns = []string{"domain", "severity", "reject_media", "reject_reports", "public_comment", "obfuscate"}
+
+// parseBlocklist parses a blocklist in Mastodon's CSV export format or FediBlockHole's JSON format.
+// If format is empty it's detected from the content. Every invalid entry is returned as a
+// *blocklistError.
+func parseBlocklist(content []byte, format string) ([]domainBlock, []error) {
+	if format == "" {
+		format = blocklistFormatCSV
+		if trimmed := bytes.TrimSpace(content); len(trimmed) > 0 && (trimmed[0] == '[' || trimmed[0] == '{') {
+			format = blocklistFormatJSON
+		}
+	}
+
+	var blocks []domainBlock
+	var lines []int
+	var errs []error
+	switch format {
+	case blocklistFormatCSV:
+		blocks, lines, errs = parseBlocklistCSV(content)
+	case blocklistFormatJSON:
+		blocks, lines, errs = parseBlocklistJSON(content)
+	default:
+		return nil, []error{fmt.Errorf("unknown format %q", format)}
+	}
+
+	// every domain can only be blocked once
+	seen := map[string]int{}
+	for i, block := range blocks {
+		if line, ok := seen[block.Domain]; ok {
+			errs = append(errs, &blocklistError{Line: lines[i], Err: fmt.Errorf("duplicate domain %s, first listed on line %d", block.Domain, line)})
+
+			continue
+		}
+		seen[block.Domain] = lines[i]
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	return blocks, nil
+}
+
+func parseBlocklistCSV(content []byte) ([]domainBlock, []int, []error) {
+	r := csv.NewReader(bytes.NewReader(content))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	var blocks []domainBlock
+	var lines []int
+	var errs []error
+	var columns []string
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				errs = append(errs, &blocklistError{Line: parseErr.Line, Err: parseErr.Err})
+
+				continue
+			}
+
+			return nil, nil, []error{err}
+		}
+
+		line, _ := r.FieldPos(0)
+
+		// the header is optional, and may prefix the column names with #
+		if columns == nil {
+			columns = blocklistCSVColumns
+			if isBlocklistCSVHeader(record) {
+				columns = make([]string, len(record))
+				for i, name := range record {
+					columns[i] = strings.TrimPrefix(strings.TrimSpace(name), "#")
+				}
+
+				continue
+			}
+		}
+
+		// skip blank lines and comments
+		if len(record) == 0 || (len(record) == 1 && strings.TrimSpace(record[0]) == "") || strings.HasPrefix(record[0], "#") {
+			continue
+		}
+
+		fields := map[string]string{}
+		for i, value := range record {
+			if i < len(columns) {
+				fields[columns[i]] = strings.TrimSpace(value)
+			}
+		}
+
+		block, err := newBlocklistEntry(fields)
+		if err != nil {
+			errs = append(errs, &blocklistError{Line: line, Err: err})
+
+			continue
+		}
+		blocks = append(blocks, block)
+		lines = append(lines, line)
+	}
+
+	return blocks, lines, errs
+}
+
+// isBlocklistCSVHeader returns true if record names the columns, which always include the domain.
+func isBlocklistCSVHeader(record []string) bool {
+	for _, name := range record {
+		if strings.TrimPrefix(strings.TrimSpace(name), "#") == "domain" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func parseBlocklistJSON(content []byte) ([]domainBlock, []int, []error) {
+	dec := json.NewDecoder(bytes.NewReader(content))
+
+	// FediBlockHole writes a list of blocks
+	tok, err := dec.Token()
+	if err != nil || tok != json.Delim('[') {
+		return nil, nil, []error{&blocklistError{Line: lineAt(content, dec.InputOffset()), Err: errors.New("expected a list of domain blocks")}}
+	}
+
+	var blocks []domainBlock
+	var lines []int
+	var errs []error
+	for dec.More() {
+		// the offset before decoding is the end of the previous element, so skip to the start of this one
+		offset := dec.InputOffset()
+		for offset < int64(len(content)) && bytes.IndexByte([]byte(" \t\r\n,"), content[offset]) >= 0 {
+			offset++
+		}
+		line := lineAt(content, offset)
+
+		var entry map[string]interface{}
+		if err := dec.Decode(&entry); err != nil {
+			return nil, nil, append(errs, &blocklistError{Line: line, Err: err})
+		}
+
+		fields := map[string]string{}
+		for key, value := range entry {
+			switch v := value.(type) {
+			case nil:
+			case string:
+				fields[key] = strings.TrimSpace(v)
+			default:
+				fields[key] = fmt.Sprint(v)
+			}
+		}
+
+		block, err := newBlocklistEntry(fields)
+		if err != nil {
+			errs = append(errs, &blocklistError{Line: line, Err: err})
+
+			continue
+		}
+		blocks = append(blocks, block)
+		lines = append(lines, line)
+	}
+
+	return blocks, lines, errs
+}
+
+// newBlocklistEntry validates the fields of a blocklist entry, filling in defaults for unset values.
+func newBlocklistEntry(fields map[string]string) (domainBlock, error) {
+	block := domainBlock{
+		Domain:   strings.ToLower(fields["domain"]),
+		Severity: domainBlockSeveritySilence,
+	}
+
+	if block.Domain == "" {
+		return block, errors.New("missing domain")
+	}
+
+	if severity := fields["severity"]; severity != "" {
+		switch severity {
+		case domainBlockSeverityNoop, domainBlockSeveritySilence, domainBlockSeveritySuspend:
+			block.Severity = severity
+		default:
+			return block, fmt.Errorf("invalid severity %q for %s, must be one of: noop, silence, suspend", severity, block.Domain)
+		}
+	}
+
+	for _, flag := range []struct {
+		key    string
+		target *bool
+	}{
+		{"reject_media", &block.RejectMedia},
+		{"reject_reports", &block.RejectReports},
+		{"obfuscate", &block.Obfuscate},
+	} {
+		key, target := flag.key, flag.target
+		value := fields[key]
+		if value == "" {
+			continue
+		}
+
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return block, fmt.Errorf("invalid %s %q for %s, must be true or false", key, value, block.Domain)
+		}
+		*target = b
+	}
+
+	if comment := fields["public_comment"]; comment != "" {
+		block.PublicComment = &comment
+	}
+	if comment := fields["private_comment"]; comment != "" {
+		block.PrivateComment = &comment
+	}
+
+	return block, nil
+}
+
+// lineAt returns the line number of offset in content.
+func lineAt(content []byte, offset int64) int {
+	if offset > int64(len(content)) {
+		offset = int64(len(content))
+	}
+
+	return bytes.Count(content[:offset], []byte("\n")) + 1
+}
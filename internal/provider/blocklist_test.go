@@ -0,0 +1,106 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParseBlocklist(t *testing.T) {
+	tables := []struct {
+		format  string
+		content string
+		want    []string
+	}{
+		// mastodon export
+		{"", "#domain,#severity,#reject_media,#reject_reports,#public_comment,#obfuscate\nbad.example,suspend,true,true,Spam,false\nmeh.example,silence,false,false,,false\n", []string{
+			"bad.example suspend media:true reports:true obfuscate:false public:Spam",
+			"meh.example silence media:false reports:false obfuscate:false public:",
+		}},
+		// columns in another order, without the # prefix
+		{"csv", "severity,domain,private_comment\nnoop,ok.example,watch\n", []string{
+			"ok.example noop media:false reports:false obfuscate:false public:",
+		}},
+		// no header, comments and blank lines
+		{"", "bad.example,suspend\n\n# comment\nMEH.example\n", []string{
+			"bad.example suspend media:false reports:false obfuscate:false public:",
+			"meh.example silence media:false reports:false obfuscate:false public:",
+		}},
+		// fediblockhole
+		{"", `[
+  {"domain": "bad.example", "severity": "suspend", "reject_media": true, "public_comment": "Spam", "obfuscate": false},
+  {"domain": "meh.example", "severity": "silence", "reject_reports": "true", "public_comment": null}
+]`, []string{
+			"bad.example suspend media:true reports:false obfuscate:false public:Spam",
+			"meh.example silence media:false reports:true obfuscate:false public:",
+		}},
+		{"json", `[]`, nil},
+	}
+
+	for i, table := range tables {
+		blocks, errs := parseBlocklist([]byte(table.content), table.format)
+		if len(errs) > 0 {
+			t.Errorf("[%d] unexpected errors: %v", i, errs)
+
+			continue
+		}
+
+		var got []string
+		for _, b := range blocks {
+			public := ""
+			if b.PublicComment != nil {
+				public = *b.PublicComment
+			}
+			got = append(got, fmt.Sprintf("%s %s media:%t reports:%t obfuscate:%t public:%s", b.Domain, b.Severity, b.RejectMedia, b.RejectReports, b.Obfuscate, public))
+		}
+		if strings.Join(got, "\n") != strings.Join(table.want, "\n") {
+			t.Errorf("[%d] unexpected blocks, got:\n%s\nwant:\n%s", i, strings.Join(got, "\n"), strings.Join(table.want, "\n"))
+		}
+	}
+}
+
+func TestParseBlocklist_Errors(t *testing.T) {
+	tables := []struct {
+		format  string
+		content string
+		want    []string
+	}{
+		{"", "#domain,#severity\nbad.example,suspend\n,silence\nworse.example,obliterate\nbad.example,noop\n", []string{
+			"line 3: missing domain",
+			`line 4: invalid severity "obliterate" for worse.example, must be one of: noop, silence, suspend`,
+			"line 5: duplicate domain bad.example, first listed on line 2",
+		}},
+		{"", "#domain,#reject_media\nbad.example,maybe\n", []string{
+			`line 2: invalid reject_media "maybe" for bad.example, must be true or false`,
+		}},
+		{"", "[\n  {\"domain\": \"bad.example\"},\n  {\"severity\": \"suspend\"}\n]", []string{
+			"line 3: missing domain",
+		}},
+		{"json", "{\"domain\": \"bad.example\"}", []string{
+			"line 1: expected a list of domain blocks",
+		}},
+		{"yaml", "", []string{
+			`unknown format "yaml"`,
+		}},
+	}
+
+	for i, table := range tables {
+		_, errs := parseBlocklist([]byte(table.content), table.format)
+
+		var got []string
+		for _, err := range errs {
+			got = append(got, err.Error())
+		}
+		if strings.Join(got, "\n") != strings.Join(table.want, "\n") {
+			t.Errorf("[%d] unexpected errors, got:\n%s\nwant:\n%s", i, strings.Join(got, "\n"), strings.Join(table.want, "\n"))
+		}
+
+		for _, err := range errs {
+			var entryErr *blocklistError
+			if strings.HasPrefix(err.Error(), "line ") && !errors.As(err, &entryErr) {
+				t.Errorf("[%d] expected *blocklistError, got: %T", i, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,311 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ provider.ResourceType = canonicalEmailBlockResourceType{}
+var _ resource.Resource = canonicalEmailBlockResource{}
+var _ resource.ResourceWithModifyPlan = canonicalEmailBlockResource{}
+var _ resource.ResourceWithValidateConfig = canonicalEmailBlockResource{}
+var _ resource.ResourceWithImportState = canonicalEmailBlockResource{}
+
+// canonicalEmailHashRegex matches a hex encoded sha256 hash.
+var canonicalEmailHashRegex = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+type canonicalEmailBlockResourceType struct{}
+
+func (t canonicalEmailBlockResourceType) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Canonical Email Block. Prevents sign ups with an email address, ignoring case, dots and anything after a plus in the local part, requires an access token with the `admin:read:canonical_email_blocks` and `admin:write:canonical_email_blocks` scopes. Blocks can't be changed once created, so changing the address creates a new block.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			// inputs
+			"canonical_email_hash": {
+				MarkdownDescription: "SHA256 hash of the canonical email address to block. Conflicts with `email`, computed from it when it's set.",
+				Optional:            true,
+				Computed:            true,
+				Type:                types.StringType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+					resource.RequiresReplace(),
+				},
+			},
+			"email": {
+				MarkdownDescription: "Email address to block, hashed by the provider so only its canonical hash is sent to the server. The address is still kept in state, since Terraform stores configured values and the plugin framework this provider uses (v0.12) has no write-only attributes, so set `canonical_email_hash` instead to keep the address out of state. Conflicts with `canonical_email_hash`.",
+				Optional:            true,
+				Sensitive:           true,
+				Type:                types.StringType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.RequiresReplace(),
+				},
+			},
+
+			// outputs
+			"id": {
+				MarkdownDescription: "identifier",
+				Type:                types.StringType,
+				Computed:            true,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
+			},
+		},
+	}, nil
+}
+
+func (t canonicalEmailBlockResourceType) NewResource(_ context.Context, in provider.Provider) (resource.Resource, diag.Diagnostics) {
+	prov, diags := convertProviderType(in)
+
+	return canonicalEmailBlockResource{
+		provider: prov,
+	}, diags
+}
+
+type canonicalEmailBlockResourceData struct {
+	CanonicalEmailHash types.String `tfsdk:"canonical_email_hash"`
+	Email              types.String `tfsdk:"email"`
+
+	ID types.String `tfsdk:"id"`
+}
+
+// canonicalEmailBlock is a canonical email block as returned by the admin API.
+type canonicalEmailBlock struct {
+	ID                 string `json:"id"`
+	CanonicalEmailHash string `json:"canonical_email_hash"`
+}
+
+// canonicalEmailHash returns the hash Mastodon blocks email by. Addresses are canonicalized by ignoring
+// case, dots and anything after a plus in the local part.
+func canonicalEmailHash(email string) string {
+	local, domain, _ := strings.Cut(strings.ToLower(email), "@")
+	local, _, _ = strings.Cut(strings.ReplaceAll(local, ".", ""), "+")
+
+	sum := sha256.Sum256([]byte(local + "@" + domain))
+
+	return hex.EncodeToString(sum[:])
+}
+
+type canonicalEmailBlockResource struct {
+	provider mastodonProvider
+}
+
+func (r canonicalEmailBlockResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data canonicalEmailBlockResourceData
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() || data.Email.IsUnknown() || data.CanonicalEmailHash.IsUnknown() {
+		return
+	}
+
+	if data.Email.IsNull() == data.CanonicalEmailHash.IsNull() {
+		resp.Diagnostics.AddError(
+			"Invalid Attribute Combination",
+			"Exactly one of email or canonical_email_hash must be set.",
+		)
+
+		return
+	}
+
+	if !data.Email.IsNull() && !strings.Contains(data.Email.Value, "@") {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("email"),
+			"Invalid Attribute Value",
+			"Attribute email value must be an email address.",
+		)
+	}
+
+	if !data.CanonicalEmailHash.IsNull() && !canonicalEmailHashRegex.MatchString(data.CanonicalEmailHash.Value) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("canonical_email_hash"),
+			"Invalid Attribute Value",
+			"Attribute canonical_email_hash value must be a lowercase hex encoded SHA256 hash.",
+		)
+	}
+}
+
+func (r canonicalEmailBlockResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// nothing to check when destroying
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.provider.requireCapability(capAdminCanonicalEmailBlocks, "mastodon_canonical_email_block")...)
+
+	var plan canonicalEmailBlockResourceData
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() || plan.Email.IsUnknown() {
+		return
+	}
+
+	// plan the hash of the email, so the address itself never has to be sent
+	if !plan.Email.IsNull() {
+		plan.CanonicalEmailHash = types.String{Value: canonicalEmailHash(plan.Email.Value)}
+		diags = resp.Plan.SetAttribute(ctx, path.Root("canonical_email_hash"), plan.CanonicalEmailHash)
+		resp.Diagnostics.Append(diags...)
+	}
+
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	var state canonicalEmailBlockResourceData
+
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// switching to another address with the same canonical form, or to its hash, blocks the same address
+	if plan.CanonicalEmailHash.Equal(state.CanonicalEmailHash) {
+		resp.RequiresReplace = nil
+	}
+}
+
+func (r canonicalEmailBlockResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data canonicalEmailBlockResourceData
+
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.provider.newAdminClient(ctx)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// the hash was planned from the email, so the address itself is never sent
+	params := url.Values{
+		"canonical_email_hash": {data.CanonicalEmailHash.Value},
+	}
+
+	var block canonicalEmailBlock
+	_, err := doAPI(ctx, client, http.MethodPost, "/api/v1/admin/canonical_email_blocks", params, &block)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "create canonical email block", err)
+
+		return
+	}
+
+	data.CanonicalEmailHash = types.String{Value: block.CanonicalEmailHash}
+	data.ID = types.String{Value: block.ID}
+
+	tflog.Trace(ctx, "created a resource")
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r canonicalEmailBlockResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data canonicalEmailBlockResourceData
+
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.provider.newAdminClient(ctx)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var block canonicalEmailBlock
+	_, err := doAPI(ctx, client, http.MethodGet, "/api/v1/admin/canonical_email_blocks/"+url.PathEscape(data.ID.Value), nil, &block)
+	if err != nil {
+		if isNotFound(err) {
+			resp.State.RemoveResource(ctx)
+
+			return
+		}
+
+		addAPIError(&resp.Diagnostics, "read canonical email block", err)
+
+		return
+	}
+
+	data.CanonicalEmailHash = types.String{Value: block.CanonicalEmailHash}
+	data.ID = types.String{Value: block.ID}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r canonicalEmailBlockResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data canonicalEmailBlockResourceData
+
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	var state canonicalEmailBlockResourceData
+
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// inputs only change without replacement when the canonical address is the same
+	data.CanonicalEmailHash = state.CanonicalEmailHash
+	data.ID = state.ID
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r canonicalEmailBlockResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data canonicalEmailBlockResourceData
+
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.provider.newAdminClient(ctx)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, err := doAPI(ctx, client, http.MethodDelete, "/api/v1/admin/canonical_email_blocks/"+url.PathEscape(data.ID.Value), nil, nil)
+	if err != nil && !isNotFound(err) {
+		addAPIError(&resp.Diagnostics, "delete canonical email block", err)
+	}
+}
+
+func (r canonicalEmailBlockResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
@@ -0,0 +1,116 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/feditools/terraform-provider-mastodon/internal/fakemastodon"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// testAccCanonicalEmailHash is the sha256 hash of evader@example.com.
+const testAccCanonicalEmailHash = "ac9833382c566ac5d0c0c9e6aa21c97d8e594c6aac4cf4d0d5d84f97b13241b0"
+
+func TestCanonicalEmailHash(t *testing.T) {
+	tables := []string{
+		"evader@example.com",
+		"Evader@Example.com",
+		"e.vader@example.com",
+		"evader+spam@example.com",
+		"E.va.der+more+spam@EXAMPLE.COM",
+	}
+
+	for i, email := range tables {
+		if got := canonicalEmailHash(email); got != testAccCanonicalEmailHash {
+			t.Errorf("[%d] unexpected hash for %s, got: %s, want: %s", i, email, got, testAccCanonicalEmailHash)
+		}
+	}
+
+	if got := canonicalEmailHash("evader@example.org"); got == testAccCanonicalEmailHash {
+		t.Errorf("expected a different hash for a different domain")
+	}
+}
+
+func TestAccCanonicalEmailBlockResource(t *testing.T) {
+	s := fakemastodon.New()
+	defer s.Close()
+
+	token := s.AddAdminToken()
+
+	var id string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(_ *terraform.State) error {
+			if s.CanonicalEmailBlock(testAccCanonicalEmailHash) != nil {
+				return fmt.Errorf("canonical email block wasn't deleted")
+			}
+
+			return nil
+		},
+		Steps: []resource.TestStep{
+			// Validation testing
+			{
+				Config:      testAccCanonicalEmailBlockResourceConfig(s, token, ""),
+				ExpectError: regexp.MustCompile("Exactly one of email or canonical_email_hash must be set"),
+			},
+			// Create and Read testing
+			{
+				Config: testAccCanonicalEmailBlockResourceConfig(s, token, `
+	email = "e.vader+spam@example.com"
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("mastodon_canonical_email_block.test", "id"),
+					resource.TestCheckResourceAttr("mastodon_canonical_email_block.test", "canonical_email_hash", testAccCanonicalEmailHash),
+					testAccCheckResourceID("mastodon_canonical_email_block.test", &id),
+				),
+			},
+			// Same canonical address testing, not replaced
+			{
+				Config: testAccCanonicalEmailBlockResourceConfig(s, token, `
+	canonical_email_hash = "`+testAccCanonicalEmailHash+`"
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("mastodon_canonical_email_block.test", "email"),
+					testAccCheckResourceID("mastodon_canonical_email_block.test", &id),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "mastodon_canonical_email_block.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Drift testing, block deleted outside of terraform
+			{
+				PreConfig: func() {
+					s.RemoveCanonicalEmailBlock(testAccCanonicalEmailHash)
+				},
+				Config: testAccCanonicalEmailBlockResourceConfig(s, token, `
+	canonical_email_hash = "`+testAccCanonicalEmailHash+`"
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					func(_ *terraform.State) error {
+						if s.CanonicalEmailBlock(testAccCanonicalEmailHash) == nil {
+							return fmt.Errorf("canonical email block wasn't recreated")
+						}
+
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+const testAccCanonicalEmailBlockResourceConfigTmpl = `
+resource "mastodon_canonical_email_block" "test" {
+%[1]s}
+`
+
+func testAccCanonicalEmailBlockResourceConfig(s *fakemastodon.Server, token, attributes string) string {
+	return testAccProviderConfigWithToken(s, token) + fmt.Sprintf(testAccCanonicalEmailBlockResourceConfigTmpl, attributes)
+}
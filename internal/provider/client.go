@@ -1,25 +1,43 @@
 package provider
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/mattn/go-mastodon"
+	"github.com/tomnomnom/linkheader"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
 )
 
 func (p *mastodonProvider) server() string {
 	return p.schema + "://" + p.domain
 }
 
-func (p *mastodonProvider) getAccessToken() string {
-	p.accessTokenLock.RLock()
-	defer p.accessTokenLock.RUnlock()
+// newMastodonClient returns a client for config that sends its requests through the provider's transport.
+func (p *mastodonProvider) newMastodonClient(config *mastodon.Config) *mastodon.Client {
+	client := mastodon.NewClient(config)
+	client.Transport = p.transport
 
-	return p.accessToken
+	return client
 }
 
-func (p *mastodonProvider) newAuthenticatedClient(ctx context.Context, clientID, clientSecret, accessToken string) (*mastodon.Client, error) {
+// httpClient returns an http client that sends its requests through the provider's transport.
+func (p *mastodonProvider) httpClient() http.Client {
+	return http.Client{
+		Transport: p.transport,
+	}
+}
+
+func (p *mastodonProvider) newAuthenticatedClient(ctx context.Context, clientID, clientSecret, scopes, accessToken string) (*mastodon.Client, error) {
 	// use given access token
 	if accessToken != "" {
-		return mastodon.NewClient(&mastodon.Config{
+		return p.newMastodonClient(&mastodon.Config{
 			Server:       p.server(),
 			ClientID:     clientID,
 			ClientSecret: clientSecret,
@@ -27,37 +45,340 @@ func (p *mastodonProvider) newAuthenticatedClient(ctx context.Context, clientID,
 		}), nil
 	}
 
-	// check cache for access token
-	if cachedAccessToken := p.getAccessToken(); cachedAccessToken != "" {
-		return mastodon.NewClient(&mastodon.Config{
-			Server:       p.server(),
-			ClientID:     clientID,
-			ClientSecret: clientSecret,
-			AccessToken:  cachedAccessToken,
-		}), nil
+	// get access token from cache, authenticating the app if needed
+	key := tokenCacheKey{
+		clientID: clientID,
+		scopes:   scopes,
+	}
+	refresh := func(ctx context.Context) (string, error) {
+		return p.requestAppToken(ctx, clientID, clientSecret, scopes)
 	}
 
-	p.accessTokenLock.Lock()
-	defer p.accessTokenLock.Unlock()
+	token, err := p.appTokens.get(key, func() (string, error) { return refresh(ctx) })
+	if err != nil {
+		return nil, err
+	}
 
-	client := mastodon.NewClient(&mastodon.Config{
+	client := p.newMastodonClient(&mastodon.Config{
 		Server:       p.server(),
 		ClientID:     clientID,
 		ClientSecret: clientSecret,
+		AccessToken:  token,
 	})
+	client.Transport = &tokenRefreshTransport{
+		base:    p.transport,
+		cache:   p.appTokens,
+		key:     key,
+		refresh: refresh,
+	}
+
+	return client, nil
+}
+
+// requestAppToken obtains an access token for the application using the client credentials grant.
+func (p *mastodonProvider) requestAppToken(ctx context.Context, clientID, clientSecret, scopes string) (string, error) {
+	params := url.Values{
+		"client_id":     {clientID},
+		"client_secret": {clientSecret},
+		"grant_type":    {"client_credentials"},
+		"redirect_uri":  {"urn:ietf:wg:oauth:2.0:oob"},
+	}
+	if scopes != "" {
+		params.Set("scope", scopes)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.server()+"/oauth/token", strings.NewReader(params.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	httpClient := p.httpClient()
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	if err := checkResponse(resp); err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var res struct {
+		AccessToken string `json:"access_token"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&res)
+	if err != nil {
+		return "", err
+	}
+	if res.AccessToken == "" {
+		return "", errors.New("empty access token")
+	}
 
-	err := client.AuthenticateApp(ctx)
+	return res.AccessToken, nil
+}
+
+// revokeAppToken revokes a token issued to the application.
+func (p *mastodonProvider) revokeAppToken(ctx context.Context, clientID, clientSecret, token string) error {
+	params := url.Values{
+		"client_id":     {clientID},
+		"client_secret": {clientSecret},
+		"token":         {token},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.server()+"/oauth/revoke", strings.NewReader(params.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	httpClient := p.httpClient()
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	if err := checkResponse(resp); err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+// doAPI sends a request to the API, decoding the response into res unless it's nil. GET parameters are
+// sent in the query, all others as a form. The response headers are returned so callers can follow
+// pagination links. Unsuccessful responses are returned as an *apiError.
+func doAPI(ctx context.Context, client *mastodon.Client, method, path string, params url.Values, res interface{}) (http.Header, error) {
+	u, err := url.Parse(client.Config.Server)
 	if err != nil {
 		return nil, err
 	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + path
 
-	p.accessToken = client.Config.AccessToken
+	var body io.Reader
+	if method == http.MethodGet {
+		u.RawQuery = params.Encode()
+	} else if params != nil {
+		body = strings.NewReader(params.Encode())
+	}
 
-	return client, nil
+	contentType := ""
+	if body != nil {
+		contentType = "application/x-www-form-urlencoded"
+	}
+
+	return sendAPI(ctx, client, method, u.String(), body, contentType, res)
+}
+
+// apiFile is a file uploaded with doMultipartAPI.
+type apiFile struct {
+	field   string
+	name    string
+	content []byte
+}
+
+// doMultipartAPI is like doAPI, but sends params and files as a multipart form, which endpoints taking
+// uploads require.
+func doMultipartAPI(ctx context.Context, client *mastodon.Client, method, path string, params url.Values, files []apiFile, res interface{}) (http.Header, error) {
+	u, err := url.Parse(client.Config.Server)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + path
+
+	// the form is buffered so the request can be retried
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for key, values := range params {
+		for _, value := range values {
+			if err := w.WriteField(key, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, file := range files {
+		part, err := w.CreateFormFile(file.field, file.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := part.Write(file.content); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return sendAPI(ctx, client, method, u.String(), bytes.NewReader(body.Bytes()), w.FormDataContentType(), res)
+}
+
+// sendAPI sends a request for doAPI and doMultipartAPI.
+func sendAPI(ctx context.Context, client *mastodon.Client, method, u string, body io.Reader, contentType string, res interface{}) (http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if client.Config.AccessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+client.Config.AccessToken)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkResponse(resp); err != nil {
+		return resp.Header, err
+	}
+	defer resp.Body.Close()
+
+	if res != nil {
+		if err := json.NewDecoder(resp.Body).Decode(res); err != nil {
+			return nil, err
+		}
+	}
+
+	return resp.Header, nil
+}
+
+// listAll requests every page of a paginated endpoint, following the Link header's next relation.
+func listAll[T any](ctx context.Context, client *mastodon.Client, path string, params url.Values) ([]T, error) {
+	var all []T
+
+	for path != "" {
+		var page []T
+		header, err := doAPI(ctx, client, http.MethodGet, path, params, &page)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+
+		// the next link carries every parameter needed for the following page
+		path, params = "", nil
+		for _, link := range linkheader.Parse(header.Get("Link")) {
+			if link.Rel != "next" {
+				continue
+			}
+
+			next, err := url.Parse(link.URL)
+			if err != nil {
+				return nil, err
+			}
+			path, params = next.Path, next.Query()
+		}
+	}
+
+	return all, nil
+}
+
+// newAdminClient returns a client for the admin API, which requires the provider's access token to
+// belong to a user with the relevant admin permissions.
+func (p *mastodonProvider) newAdminClient(ctx context.Context) (*mastodon.Client, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if p.accessToken == "" {
+		diags.AddError(
+			"Missing Access Token",
+			"The admin API requires an access token belonging to a user with admin permissions. "+
+				"Set access_token in the provider configuration or use the "+envAccessToken+" environment variable.",
+		)
+
+		return nil, diags
+	}
+
+	client, err := p.newAuthenticatedClient(ctx, "", "", "", p.accessToken)
+	if err != nil {
+		addAPIError(&diags, "create admin client", err)
+	}
+
+	return client, diags
+}
+
+// newUserClient returns a client acting as the user accessToken belongs to, using the provider's access
+// token if it's empty.
+func (p *mastodonProvider) newUserClient(ctx context.Context, accessToken string) (*mastodon.Client, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if accessToken == "" {
+		accessToken = p.accessToken
+	}
+	if accessToken == "" {
+		diags.AddError(
+			"Missing Access Token",
+			"Managing an account requires an access token belonging to it. "+
+				"Set access_token in the provider configuration or use the "+envAccessToken+" environment variable.",
+		)
+
+		return nil, diags
+	}
+
+	client, err := p.newAuthenticatedClient(ctx, "", "", "", accessToken)
+	if err != nil {
+		addAPIError(&diags, "create client", err)
+	}
+
+	return client, diags
+}
+
+// newClient returns a client using the provider's access token, falling back
+// to an unauthenticated client if no access token was configured.
+func (p *mastodonProvider) newClient() *mastodon.Client {
+	if p.accessToken == "" {
+		return p.newUnauthenticatedClient()
+	}
+
+	return p.newMastodonClient(&mastodon.Config{
+		Server:      p.server(),
+		AccessToken: p.accessToken,
+	})
 }
 
 func (p *mastodonProvider) newUnauthenticatedClient() *mastodon.Client {
-	return mastodon.NewClient(&mastodon.Config{
+	return p.newMastodonClient(&mastodon.Config{
 		Server: p.server(),
 	})
 }
+
+// tokenRefreshTransport evicts a cached application token when the server rejects it and
+// retries the request once with a freshly issued token.
+type tokenRefreshTransport struct {
+	base    http.RoundTripper
+	cache   *tokenCache
+	key     tokenCacheKey
+	refresh func(ctx context.Context) (string, error)
+}
+
+func (t *tokenRefreshTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	// request can't be replayed
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+
+	staleToken := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	t.cache.evict(t.key, staleToken)
+
+	token, refreshErr := t.cache.get(t.key, func() (string, error) { return t.refresh(req.Context()) })
+	if refreshErr != nil || token == staleToken {
+		// hand back the original 401 so callers see the app is no longer authorized
+		return resp, nil
+	}
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		retry.Body, refreshErr = req.GetBody()
+		if refreshErr != nil {
+			return resp, nil
+		}
+	}
+	retry.Header.Set("Authorization", "Bearer "+token)
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBodySize))
+	_ = resp.Body.Close()
+
+	return t.base.RoundTrip(retry)
+}
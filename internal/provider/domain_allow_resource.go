@@ -0,0 +1,253 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ provider.ResourceType = domainAllowResourceType{}
+var _ resource.Resource = domainAllowResource{}
+var _ resource.ResourceWithModifyPlan = domainAllowResource{}
+var _ resource.ResourceWithImportState = domainAllowResource{}
+
+type domainAllowResourceType struct{}
+
+func (t domainAllowResourceType) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Domain Allow. Allows federation with a domain when the instance runs in limited federation mode, requires an access token with the `admin:read:domain_allows` and `admin:write:domain_allows` scopes.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			// inputs
+			"domain": {
+				MarkdownDescription: "Domain to allow",
+				Required:            true,
+				Type:                types.StringType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.RequiresReplace(),
+				},
+			},
+
+			// outputs
+			"created_at": {
+				MarkdownDescription: "When the domain was allowed",
+				Type:                types.StringType,
+				Computed:            true,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
+			},
+			"id": {
+				MarkdownDescription: "identifier",
+				Type:                types.StringType,
+				Computed:            true,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
+			},
+		},
+	}, nil
+}
+
+func (t domainAllowResourceType) NewResource(_ context.Context, in provider.Provider) (resource.Resource, diag.Diagnostics) {
+	prov, diags := convertProviderType(in)
+
+	return domainAllowResource{
+		provider: prov,
+	}, diags
+}
+
+type domainAllowResourceData struct {
+	Domain types.String `tfsdk:"domain"`
+
+	CreatedAt types.String `tfsdk:"created_at"`
+	ID        types.String `tfsdk:"id"`
+}
+
+// domainAllow is a domain allow as returned by the admin API.
+type domainAllow struct {
+	ID        string    `json:"id"`
+	Domain    string    `json:"domain"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// update sets the data to the values of allow.
+func (d *domainAllowResourceData) update(allow *domainAllow) {
+	d.CreatedAt = types.String{Value: allow.CreatedAt.Format(time.RFC3339)}
+	// the server lowercases domains, so a configured domain that only differs in case is kept
+	if !strings.EqualFold(d.Domain.Value, allow.Domain) {
+		d.Domain = types.String{Value: allow.Domain}
+	}
+	d.ID = types.String{Value: allow.ID}
+}
+
+type domainAllowResource struct {
+	provider mastodonProvider
+}
+
+func (r domainAllowResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// nothing to check when destroying
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.provider.requireCapability(capAdminDomainAllows, "mastodon_domain_allow")...)
+}
+
+func (r domainAllowResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data domainAllowResourceData
+
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.provider.newAdminClient(ctx)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	params := url.Values{
+		"domain": {data.Domain.Value},
+	}
+
+	var allow domainAllow
+	_, err := doAPI(ctx, client, http.MethodPost, "/api/v1/admin/domain_allows", params, &allow)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "create domain allow", err)
+
+		return
+	}
+
+	data.update(&allow)
+
+	tflog.Trace(ctx, "created a resource")
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r domainAllowResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data domainAllowResourceData
+
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.provider.newAdminClient(ctx)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var allow domainAllow
+	_, err := doAPI(ctx, client, http.MethodGet, "/api/v1/admin/domain_allows/"+url.PathEscape(data.ID.Value), nil, &allow)
+	if err != nil {
+		if isNotFound(err) {
+			resp.State.RemoveResource(ctx)
+
+			return
+		}
+
+		addAPIError(&resp.Diagnostics, "read domain allow", err)
+
+		return
+	}
+
+	data.update(&allow)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r domainAllowResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data domainAllowResourceData
+
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// the domain requires replacement, so the allow is unchanged
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r domainAllowResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data domainAllowResourceData
+
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.provider.newAdminClient(ctx)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, err := doAPI(ctx, client, http.MethodDelete, "/api/v1/admin/domain_allows/"+url.PathEscape(data.ID.Value), nil, nil)
+	if err != nil && !isNotFound(err) {
+		addAPIError(&resp.Diagnostics, "delete domain allow", err)
+	}
+}
+
+func (r domainAllowResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	client, diags := r.provider.newAdminClient(ctx)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// the api can't look allows up by domain, so search the full list
+	allows, err := listAll[domainAllow](ctx, client, "/api/v1/admin/domain_allows", nil)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "list domain allows", err)
+
+		return
+	}
+
+	domain := strings.ToLower(strings.TrimSpace(req.ID))
+	for i := range allows {
+		if allows[i].Domain != domain {
+			continue
+		}
+
+		var data domainAllowResourceData
+		data.update(&allows[i])
+
+		diags = resp.State.Set(ctx, &data)
+		resp.Diagnostics.Append(diags...)
+
+		return
+	}
+
+	resp.Diagnostics.AddError(
+		"Domain Allow Not Found",
+		"No domain allow exists for "+req.ID+".",
+	)
+}
@@ -0,0 +1,87 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/feditools/terraform-provider-mastodon/internal/fakemastodon"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccDomainAllowResource(t *testing.T) {
+	s := fakemastodon.New()
+	defer s.Close()
+
+	token := s.AddAdminToken()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(_ *terraform.State) error {
+			if s.DomainAllow("friends.example") != nil || s.DomainAllow("pals.example") != nil {
+				return fmt.Errorf("domain allow wasn't deleted")
+			}
+
+			return nil
+		},
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccDomainAllowResourceConfig(s, token, "friends.example"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("mastodon_domain_allow.test", "id"),
+					resource.TestCheckResourceAttrSet("mastodon_domain_allow.test", "created_at"),
+					resource.TestCheckResourceAttr("mastodon_domain_allow.test", "domain", "friends.example"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "mastodon_domain_allow.test",
+				ImportState:       true,
+				ImportStateId:     "friends.example",
+				ImportStateVerify: true,
+			},
+			// Replace testing
+			{
+				Config: testAccDomainAllowResourceConfig(s, token, "pals.example"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("mastodon_domain_allow.test", "domain", "pals.example"),
+					func(_ *terraform.State) error {
+						if s.DomainAllow("friends.example") != nil {
+							return fmt.Errorf("replaced domain allow wasn't deleted")
+						}
+
+						return nil
+					},
+				),
+			},
+			// Drift testing, allow deleted outside of terraform
+			{
+				PreConfig: func() {
+					s.RemoveDomainAllow("pals.example")
+				},
+				Config: testAccDomainAllowResourceConfig(s, token, "pals.example"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					func(_ *terraform.State) error {
+						if s.DomainAllow("pals.example") == nil {
+							return fmt.Errorf("domain allow wasn't recreated")
+						}
+
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+const testAccDomainAllowResourceConfigTmpl = `
+resource "mastodon_domain_allow" "test" {
+	domain = %[1]q
+}
+`
+
+func testAccDomainAllowResourceConfig(s *fakemastodon.Server, token, domain string) string {
+	return testAccProviderConfigWithToken(s, token) + fmt.Sprintf(testAccDomainAllowResourceConfigTmpl, domain)
+}
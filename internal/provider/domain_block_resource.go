@@ -0,0 +1,352 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ provider.ResourceType = domainBlockResourceType{}
+var _ resource.Resource = domainBlockResource{}
+var _ resource.ResourceWithModifyPlan = domainBlockResource{}
+var _ resource.ResourceWithImportState = domainBlockResource{}
+
+const (
+	domainBlockSeverityNoop    = "noop"
+	domainBlockSeveritySilence = "silence"
+	domainBlockSeveritySuspend = "suspend"
+)
+
+type domainBlockResourceType struct{}
+
+func (t domainBlockResourceType) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Domain Block. Limits federation with a domain, requires an access token with the `admin:read:domain_blocks` and `admin:write:domain_blocks` scopes.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			// inputs
+			"domain": {
+				MarkdownDescription: "Domain to block",
+				Required:            true,
+				Type:                types.StringType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.RequiresReplace(),
+				},
+			},
+			"obfuscate": {
+				MarkdownDescription: "Obfuscate the domain name in the public list of domain blocks. Defaults to `false`.",
+				Optional:            true,
+				Computed:            true,
+				Type:                types.BoolType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					defaultValue(types.Bool{Value: false}),
+				},
+			},
+			"private_comment": {
+				MarkdownDescription: "Comment about the block for moderators",
+				Optional:            true,
+				Type:                types.StringType,
+			},
+			"public_comment": {
+				MarkdownDescription: "Comment about the block shown to the public",
+				Optional:            true,
+				Type:                types.StringType,
+			},
+			"reject_media": {
+				MarkdownDescription: "Reject media files from the domain. Defaults to `false`.",
+				Optional:            true,
+				Computed:            true,
+				Type:                types.BoolType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					defaultValue(types.Bool{Value: false}),
+				},
+			},
+			"reject_reports": {
+				MarkdownDescription: "Reject reports from the domain. Defaults to `false`.",
+				Optional:            true,
+				Computed:            true,
+				Type:                types.BoolType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					defaultValue(types.Bool{Value: false}),
+				},
+			},
+			"severity": {
+				MarkdownDescription: "Severity of the block, one of `noop`, `silence` or `suspend`. Defaults to `silence`.",
+				Optional:            true,
+				Computed:            true,
+				Type:                types.StringType,
+				Validators: []tfsdk.AttributeValidator{
+					stringOneOf(domainBlockSeverityNoop, domainBlockSeveritySilence, domainBlockSeveritySuspend),
+				},
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					defaultValue(types.String{Value: domainBlockSeveritySilence}),
+				},
+			},
+
+			// outputs
+			"id": {
+				MarkdownDescription: "identifier",
+				Type:                types.StringType,
+				Computed:            true,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
+			},
+		},
+	}, nil
+}
+
+func (t domainBlockResourceType) NewResource(_ context.Context, in provider.Provider) (resource.Resource, diag.Diagnostics) {
+	prov, diags := convertProviderType(in)
+
+	return domainBlockResource{
+		provider: prov,
+	}, diags
+}
+
+type domainBlockResourceData struct {
+	Domain         types.String `tfsdk:"domain"`
+	Obfuscate      types.Bool   `tfsdk:"obfuscate"`
+	PrivateComment types.String `tfsdk:"private_comment"`
+	PublicComment  types.String `tfsdk:"public_comment"`
+	RejectMedia    types.Bool   `tfsdk:"reject_media"`
+	RejectReports  types.Bool   `tfsdk:"reject_reports"`
+	Severity       types.String `tfsdk:"severity"`
+
+	ID types.String `tfsdk:"id"`
+}
+
+// domainBlock is a domain block as returned by the admin API.
+type domainBlock struct {
+	ID             string  `json:"id"`
+	Domain         string  `json:"domain"`
+	Severity       string  `json:"severity"`
+	RejectMedia    bool    `json:"reject_media"`
+	RejectReports  bool    `json:"reject_reports"`
+	PrivateComment *string `json:"private_comment"`
+	PublicComment  *string `json:"public_comment"`
+	Obfuscate      bool    `json:"obfuscate"`
+}
+
+// params returns the request parameters setting every attribute of the block. Unset comments are
+// sent empty so removing them from the configuration clears them on the server.
+func (d domainBlockResourceData) params() url.Values {
+	return url.Values{
+		"severity":        {d.Severity.Value},
+		"reject_media":    {strconv.FormatBool(d.RejectMedia.Value)},
+		"reject_reports":  {strconv.FormatBool(d.RejectReports.Value)},
+		"private_comment": {d.PrivateComment.Value},
+		"public_comment":  {d.PublicComment.Value},
+		"obfuscate":       {strconv.FormatBool(d.Obfuscate.Value)},
+	}
+}
+
+// update sets the data to the values of block.
+func (d *domainBlockResourceData) update(block *domainBlock) {
+	d.ID = types.String{Value: block.ID}
+	// the server lowercases domains, so a configured domain that only differs in case is kept
+	if !strings.EqualFold(d.Domain.Value, block.Domain) {
+		d.Domain = types.String{Value: block.Domain}
+	}
+	d.Obfuscate = types.Bool{Value: block.Obfuscate}
+	d.PrivateComment = optionalString(block.PrivateComment)
+	d.PublicComment = optionalString(block.PublicComment)
+	d.RejectMedia = types.Bool{Value: block.RejectMedia}
+	d.RejectReports = types.Bool{Value: block.RejectReports}
+	d.Severity = types.String{Value: block.Severity}
+}
+
+// optionalString converts a string the server returns as null or empty when unset.
+func optionalString(s *string) types.String {
+	if s == nil || *s == "" {
+		return types.String{Null: true}
+	}
+
+	return types.String{Value: *s}
+}
+
+type domainBlockResource struct {
+	provider mastodonProvider
+}
+
+func (r domainBlockResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// nothing to check when destroying
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.provider.requireCapability(capAdminDomainBlocks, "mastodon_domain_block")...)
+}
+
+func (r domainBlockResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data domainBlockResourceData
+
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.provider.newAdminClient(ctx)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	params := data.params()
+	params.Set("domain", data.Domain.Value)
+
+	var block domainBlock
+	_, err := doAPI(ctx, client, http.MethodPost, "/api/v1/admin/domain_blocks", params, &block)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "create domain block", err)
+
+		return
+	}
+
+	data.update(&block)
+
+	tflog.Trace(ctx, "created a resource")
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r domainBlockResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data domainBlockResourceData
+
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.provider.newAdminClient(ctx)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var block domainBlock
+	_, err := doAPI(ctx, client, http.MethodGet, "/api/v1/admin/domain_blocks/"+url.PathEscape(data.ID.Value), nil, &block)
+	if err != nil {
+		if isNotFound(err) {
+			resp.State.RemoveResource(ctx)
+
+			return
+		}
+
+		addAPIError(&resp.Diagnostics, "read domain block", err)
+
+		return
+	}
+
+	data.update(&block)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r domainBlockResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data domainBlockResourceData
+
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.provider.newAdminClient(ctx)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var block domainBlock
+	_, err := doAPI(ctx, client, http.MethodPut, "/api/v1/admin/domain_blocks/"+url.PathEscape(data.ID.Value), data.params(), &block)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "update domain block", err)
+
+		return
+	}
+
+	data.update(&block)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r domainBlockResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data domainBlockResourceData
+
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.provider.newAdminClient(ctx)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, err := doAPI(ctx, client, http.MethodDelete, "/api/v1/admin/domain_blocks/"+url.PathEscape(data.ID.Value), nil, nil)
+	if err != nil && !isNotFound(err) {
+		addAPIError(&resp.Diagnostics, "delete domain block", err)
+	}
+}
+
+func (r domainBlockResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	client, diags := r.provider.newAdminClient(ctx)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// the api can't look blocks up by domain, so search the full list
+	blocks, err := listAll[domainBlock](ctx, client, "/api/v1/admin/domain_blocks", nil)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "list domain blocks", err)
+
+		return
+	}
+
+	domain := strings.ToLower(strings.TrimSpace(req.ID))
+	for i := range blocks {
+		if blocks[i].Domain != domain {
+			continue
+		}
+
+		var data domainBlockResourceData
+		data.update(&blocks[i])
+
+		diags = resp.State.Set(ctx, &data)
+		resp.Diagnostics.Append(diags...)
+
+		return
+	}
+
+	resp.Diagnostics.AddError(
+		"Domain Block Not Found",
+		"No domain block exists for "+req.ID+".",
+	)
+}
@@ -0,0 +1,144 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/feditools/terraform-provider-mastodon/internal/fakemastodon"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccDomainBlockResource(t *testing.T) {
+	s := fakemastodon.New()
+	defer s.Close()
+
+	token := s.AddAdminToken()
+
+	var id string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(_ *terraform.State) error {
+			if s.DomainBlock("bad.example") != nil {
+				return fmt.Errorf("domain block wasn't deleted")
+			}
+
+			return nil
+		},
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccDomainBlockResourceConfig(s, token, `
+	severity     = "suspend"
+	reject_media = true
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("mastodon_domain_block.test", "id"),
+					resource.TestCheckResourceAttr("mastodon_domain_block.test", "severity", "suspend"),
+					resource.TestCheckResourceAttr("mastodon_domain_block.test", "reject_media", "true"),
+					resource.TestCheckResourceAttr("mastodon_domain_block.test", "reject_reports", "false"),
+					resource.TestCheckResourceAttr("mastodon_domain_block.test", "obfuscate", "false"),
+					resource.TestCheckNoResourceAttr("mastodon_domain_block.test", "public_comment"),
+					testAccCheckResourceID("mastodon_domain_block.test", &id),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "mastodon_domain_block.test",
+				ImportState:       true,
+				ImportStateId:     "bad.example",
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: testAccDomainBlockResourceConfig(s, token, `
+	public_comment  = "spam"
+	private_comment = "reported by several users"
+	obfuscate       = true
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("mastodon_domain_block.test", "severity", "silence"),
+					resource.TestCheckResourceAttr("mastodon_domain_block.test", "reject_media", "false"),
+					resource.TestCheckResourceAttr("mastodon_domain_block.test", "public_comment", "spam"),
+					resource.TestCheckResourceAttr("mastodon_domain_block.test", "private_comment", "reported by several users"),
+					resource.TestCheckResourceAttr("mastodon_domain_block.test", "obfuscate", "true"),
+					testAccCheckResourceID("mastodon_domain_block.test", &id),
+				),
+			},
+			// Drift testing, block changed outside of terraform
+			{
+				PreConfig: func() {
+					s.UpdateDomainBlock("bad.example", func(block *fakemastodon.DomainBlock) {
+						block.Severity = "noop"
+						block.PublicComment = nil
+					})
+				},
+				Config: testAccDomainBlockResourceConfig(s, token, `
+	public_comment  = "spam"
+	private_comment = "reported by several users"
+	obfuscate       = true
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("mastodon_domain_block.test", "severity", "silence"),
+					resource.TestCheckResourceAttr("mastodon_domain_block.test", "public_comment", "spam"),
+					testAccCheckResourceID("mastodon_domain_block.test", &id),
+				),
+			},
+			// Drift testing, block deleted outside of terraform
+			{
+				PreConfig: func() {
+					s.RemoveDomainBlock("bad.example")
+				},
+				Config: testAccDomainBlockResourceConfig(s, token, ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("mastodon_domain_block.test", "domain", "bad.example"),
+					resource.TestCheckNoResourceAttr("mastodon_domain_block.test", "public_comment"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDomainBlockResource_mixedCase(t *testing.T) {
+	s := fakemastodon.New()
+	defer s.Close()
+
+	token := s.AddAdminToken()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// the server lowercases the domain, the configured domain is kept
+			{
+				Config: testAccProviderConfigWithToken(s, token) + `
+resource "mastodon_domain_block" "test" {
+	domain = "Bad.Example"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("mastodon_domain_block.test", "domain", "Bad.Example"),
+					func(_ *terraform.State) error {
+						if s.DomainBlock("bad.example") == nil {
+							return fmt.Errorf("expected bad.example to be blocked")
+						}
+
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+const testAccDomainBlockResourceConfigTmpl = `
+resource "mastodon_domain_block" "test" {
+	domain = "bad.example"
+%[1]s}
+`
+
+func testAccDomainBlockResourceConfig(s *fakemastodon.Server, token, attributes string) string {
+	return testAccProviderConfigWithToken(s, token) + fmt.Sprintf(testAccDomainBlockResourceConfigTmpl, attributes)
+}
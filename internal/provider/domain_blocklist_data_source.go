@@ -0,0 +1,176 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"os"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ provider.DataSourceType = domainBlocklistDataSourceType{}
+var _ datasource.DataSource = domainBlocklistDataSource{}
+var _ datasource.DataSourceWithValidateConfig = domainBlocklistDataSource{}
+
+type domainBlocklistDataSourceType struct{}
+
+func (t domainBlocklistDataSourceType) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Domain blocklist. Parses a list of domain blocks in Mastodon's CSV export format or FediBlockHole's JSON format, for use with `mastodon_domain_block` resources.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"content": {
+				MarkdownDescription: "Blocklist to parse. Conflicts with `file`.",
+				Type:                types.StringType,
+				Optional:            true,
+			},
+			"file": {
+				MarkdownDescription: "Path of the blocklist file to parse. Conflicts with `content`.",
+				Type:                types.StringType,
+				Optional:            true,
+			},
+			"format": {
+				MarkdownDescription: "Format of the blocklist, one of `csv` or `json`. Defaults to `json` if the blocklist starts with `[`, `csv` otherwise.",
+				Type:                types.StringType,
+				Optional:            true,
+				Validators: []tfsdk.AttributeValidator{
+					stringOneOf(blocklistFormatCSV, blocklistFormatJSON),
+				},
+			},
+
+			"blocks": {
+				MarkdownDescription: "Domain blocks keyed by domain, suitable for `for_each`",
+				Type: types.MapType{
+					ElemType: types.ObjectType{
+						AttrTypes: domainBlockTypes,
+					},
+				},
+				Computed: true,
+			},
+			"domains": {
+				MarkdownDescription: "Blocked domains in the order they're listed",
+				Type: types.ListType{
+					ElemType: types.StringType,
+				},
+				Computed: true,
+			},
+			"id": {
+				MarkdownDescription: "SHA256 hash of the blocklist",
+				Type:                types.StringType,
+				Computed:            true,
+			},
+		},
+	}, nil
+}
+
+func (t domainBlocklistDataSourceType) NewDataSource(_ context.Context, in provider.Provider) (datasource.DataSource, diag.Diagnostics) {
+	prov, diags := convertProviderType(in)
+
+	return domainBlocklistDataSource{
+		provider: prov,
+	}, diags
+}
+
+type domainBlocklistDataSourceData struct {
+	Content types.String `tfsdk:"content"`
+	File    types.String `tfsdk:"file"`
+	Format  types.String `tfsdk:"format"`
+
+	Blocks  types.Map    `tfsdk:"blocks"`
+	Domains types.List   `tfsdk:"domains"`
+	ID      types.String `tfsdk:"id"`
+}
+
+type domainBlocklistDataSource struct {
+	provider mastodonProvider
+}
+
+func (d domainBlocklistDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var data domainBlocklistDataSourceData
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() || data.Content.IsUnknown() || data.File.IsUnknown() {
+		return
+	}
+
+	if data.Content.IsNull() == data.File.IsNull() {
+		resp.Diagnostics.AddError(
+			"Invalid Attribute Combination",
+			"Exactly one of content or file must be set.",
+		)
+	}
+}
+
+func (d domainBlocklistDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data domainBlocklistDataSourceData
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	source := path.Root("content")
+	content := []byte(data.Content.Value)
+	if !data.File.IsNull() {
+		source = path.Root("file")
+
+		var err error
+		content, err = os.ReadFile(data.File.Value)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				source,
+				"Unable to Read Blocklist",
+				"Unable to read blocklist file "+data.File.Value+": "+err.Error(),
+			)
+
+			return
+		}
+	}
+
+	blocks, errs := parseBlocklist(content, data.Format.Value)
+	for _, err := range errs {
+		summary := "Invalid Blocklist"
+		var entryErr *blocklistError
+		if errors.As(err, &entryErr) {
+			summary = "Invalid Blocklist Entry"
+		}
+
+		resp.Diagnostics.AddAttributeError(source, summary, err.Error())
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	sum := sha256.Sum256(content)
+	data.ID = types.String{Value: hex.EncodeToString(sum[:])}
+
+	data.Blocks = types.Map{
+		ElemType: types.ObjectType{AttrTypes: domainBlockTypes},
+		Elems:    make(map[string]attr.Value, len(blocks)),
+	}
+	data.Domains = types.List{
+		ElemType: types.StringType,
+		Elems:    make([]attr.Value, 0, len(blocks)),
+	}
+	for _, block := range blocks {
+		data.Blocks.Elems[block.Domain] = domainBlockObject(block)
+		data.Domains.Elems = append(data.Domains.Elems, types.String{Value: block.Domain})
+	}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
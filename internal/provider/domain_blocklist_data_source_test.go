@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/feditools/terraform-provider-mastodon/internal/fakemastodon"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDomainBlocklistDataSource(t *testing.T) {
+	s := fakemastodon.New()
+	defer s.Close()
+
+	file := filepath.Join(t.TempDir(), "blocklist.json")
+	err := os.WriteFile(file, []byte(`[{"domain": "bad.example", "severity": "suspend", "reject_media": true}]`), 0o600)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDomainBlocklistDataSourceConfig(s, `
+	content = "#domain,#severity,#public_comment\nbad.example,suspend,Spam\nmeh.example,silence,\n"
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.mastodon_domain_blocklist.test", "id"),
+					resource.TestCheckResourceAttr("data.mastodon_domain_blocklist.test", "domains.#", "2"),
+					resource.TestCheckResourceAttr("data.mastodon_domain_blocklist.test", "domains.0", "bad.example"),
+					resource.TestCheckResourceAttr("data.mastodon_domain_blocklist.test", "blocks.%", "2"),
+					resource.TestCheckResourceAttr("data.mastodon_domain_blocklist.test", "blocks.bad.example.severity", "suspend"),
+					resource.TestCheckResourceAttr("data.mastodon_domain_blocklist.test", "blocks.bad.example.public_comment", "Spam"),
+					resource.TestCheckResourceAttr("data.mastodon_domain_blocklist.test", "blocks.meh.example.reject_media", "false"),
+				),
+			},
+			{
+				Config: testAccDomainBlocklistDataSourceConfig(s, fmt.Sprintf(`
+	file = %q
+`, file)),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mastodon_domain_blocklist.test", "domains.#", "1"),
+					resource.TestCheckResourceAttr("data.mastodon_domain_blocklist.test", "blocks.bad.example.reject_media", "true"),
+				),
+			},
+			{
+				Config: testAccDomainBlocklistDataSourceConfig(s, `
+	content = "#domain,#severity\nbad.example,obliterate\n"
+`),
+				ExpectError: regexp.MustCompile(`line 2: invalid severity "obliterate"`),
+			},
+		},
+	})
+}
+
+const testAccDomainBlocklistDataSourceConfigTmpl = `
+data "mastodon_domain_blocklist" "test" {
+%[1]s}
+`
+
+func testAccDomainBlocklistDataSourceConfig(s *fakemastodon.Server, attributes string) string {
+	return testAccProviderConfig(s) + fmt.Sprintf(testAccDomainBlocklistDataSourceConfigTmpl, attributes)
+}
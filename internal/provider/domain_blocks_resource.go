@@ -0,0 +1,540 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/mattn/go-mastodon"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ provider.ResourceType = domainBlocksResourceType{}
+var _ resource.Resource = domainBlocksResource{}
+var _ resource.ResourceWithModifyPlan = domainBlocksResource{}
+var _ resource.ResourceWithValidateConfig = domainBlocksResource{}
+var _ resource.ResourceWithImportState = domainBlocksResource{}
+
+// domainBlocksParallelism is the number of domain blocks changed at the same time.
+const domainBlocksParallelism = 4
+
+type domainBlocksResourceType struct{}
+
+func (t domainBlocksResourceType) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Domain Blocks. Manages many domain blocks as one resource, only changing the blocks that differ from the server, requires an access token with the `admin:read:domain_blocks` and `admin:write:domain_blocks` scopes. Don't manage the same domain with both this resource and `mastodon_domain_block`.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			// inputs
+			"authoritative": {
+				MarkdownDescription: "Delete blocks on the server that aren't listed in `blocks`, instead of ignoring them. Defaults to `false`.",
+				Optional:            true,
+				Computed:            true,
+				Type:                types.BoolType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					defaultValue(types.Bool{Value: false}),
+				},
+			},
+			"blocks": {
+				MarkdownDescription: "Domain blocks",
+				Required:            true,
+				Attributes: tfsdk.SetNestedAttributes(map[string]tfsdk.Attribute{
+					"domain": {
+						MarkdownDescription: "Domain to block",
+						Required:            true,
+						Type:                types.StringType,
+					},
+					"obfuscate": {
+						MarkdownDescription: "Obfuscate the domain name in the public list of domain blocks. Defaults to `false`.",
+						Optional:            true,
+						Type:                types.BoolType,
+					},
+					"private_comment": {
+						MarkdownDescription: "Comment about the block for moderators",
+						Optional:            true,
+						Type:                types.StringType,
+					},
+					"public_comment": {
+						MarkdownDescription: "Comment about the block shown to the public",
+						Optional:            true,
+						Type:                types.StringType,
+					},
+					"reject_media": {
+						MarkdownDescription: "Reject media files from the domain. Defaults to `false`.",
+						Optional:            true,
+						Type:                types.BoolType,
+					},
+					"reject_reports": {
+						MarkdownDescription: "Reject reports from the domain. Defaults to `false`.",
+						Optional:            true,
+						Type:                types.BoolType,
+					},
+					"severity": {
+						MarkdownDescription: "Severity of the block, one of `noop`, `silence` or `suspend`. Defaults to `silence`.",
+						Optional:            true,
+						Type:                types.StringType,
+						Validators: []tfsdk.AttributeValidator{
+							stringOneOf(domainBlockSeverityNoop, domainBlockSeveritySilence, domainBlockSeveritySuspend),
+						},
+					},
+				}),
+			},
+
+			// outputs
+			"id": {
+				MarkdownDescription: "identifier",
+				Type:                types.StringType,
+				Computed:            true,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
+			},
+		},
+	}, nil
+}
+
+func (t domainBlocksResourceType) NewResource(_ context.Context, in provider.Provider) (resource.Resource, diag.Diagnostics) {
+	prov, diags := convertProviderType(in)
+
+	return domainBlocksResource{
+		provider: prov,
+	}, diags
+}
+
+type domainBlocksResourceData struct {
+	Authoritative types.Bool `tfsdk:"authoritative"`
+	Blocks        types.Set  `tfsdk:"blocks"`
+
+	ID types.String `tfsdk:"id"`
+}
+
+// entries returns the configured blocks keyed by their lowercased domain.
+func (d domainBlocksResourceData) entries() map[string]types.Object {
+	entries := make(map[string]types.Object, len(d.Blocks.Elems))
+	for _, elem := range d.Blocks.Elems {
+		obj, ok := elem.(types.Object)
+		if !ok || obj.IsUnknown() {
+			continue
+		}
+
+		domain, ok := obj.Attrs["domain"].(types.String)
+		if !ok || domain.IsNull() || domain.IsUnknown() {
+			continue
+		}
+
+		entries[strings.ToLower(domain.Value)] = obj
+	}
+
+	return entries
+}
+
+// update sets the blocks to the blocks on the server. Blocks that match their configured entry keep
+// its form, so unset attributes don't show up as changed. Unless the data is authoritative only the
+// blocks in managed are kept.
+func (d *domainBlocksResourceData) update(blocks []domainBlock, managed map[string]types.Object) {
+	d.Blocks = types.Set{
+		ElemType: types.ObjectType{AttrTypes: domainBlockTypes},
+		Elems:    make([]attr.Value, 0, len(blocks)),
+	}
+
+	for _, block := range blocks {
+		entry, ok := managed[block.Domain]
+		if !ok && !d.Authoritative.Value {
+			continue
+		}
+
+		if ok && domainBlockEqual(domainBlockEntry(entry), block) {
+			d.Blocks.Elems = append(d.Blocks.Elems, entry)
+
+			continue
+		}
+
+		d.Blocks.Elems = append(d.Blocks.Elems, domainBlockObject(block))
+	}
+}
+
+// domainBlockEntry converts a configured block to a domain block, filling in the server's defaults for
+// unset attributes.
+func domainBlockEntry(obj types.Object) domainBlock {
+	block := domainBlock{
+		Severity: domainBlockSeveritySilence,
+	}
+
+	if v, ok := obj.Attrs["domain"].(types.String); ok {
+		block.Domain = strings.ToLower(v.Value)
+	}
+	if v, ok := obj.Attrs["severity"].(types.String); ok && !v.IsNull() {
+		block.Severity = v.Value
+	}
+	if v, ok := obj.Attrs["obfuscate"].(types.Bool); ok {
+		block.Obfuscate = v.Value
+	}
+	if v, ok := obj.Attrs["reject_media"].(types.Bool); ok {
+		block.RejectMedia = v.Value
+	}
+	if v, ok := obj.Attrs["reject_reports"].(types.Bool); ok {
+		block.RejectReports = v.Value
+	}
+	if v, ok := obj.Attrs["private_comment"].(types.String); ok && !v.IsNull() {
+		block.PrivateComment = &v.Value
+	}
+	if v, ok := obj.Attrs["public_comment"].(types.String); ok && !v.IsNull() {
+		block.PublicComment = &v.Value
+	}
+
+	return block
+}
+
+// domainBlockEqual returns true if a and b block their domain the same way, treating unset comments
+// as empty.
+func domainBlockEqual(a, b domainBlock) bool {
+	comment := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+
+		return *s
+	}
+
+	return a.Domain == b.Domain &&
+		a.Severity == b.Severity &&
+		a.Obfuscate == b.Obfuscate &&
+		a.RejectMedia == b.RejectMedia &&
+		a.RejectReports == b.RejectReports &&
+		comment(a.PrivateComment) == comment(b.PrivateComment) &&
+		comment(a.PublicComment) == comment(b.PublicComment)
+}
+
+// domainBlockParams returns the request parameters setting every attribute of block.
+func domainBlockParams(block domainBlock) url.Values {
+	data := domainBlockResourceData{
+		Severity:       types.String{Value: block.Severity},
+		Obfuscate:      types.Bool{Value: block.Obfuscate},
+		PrivateComment: optionalString(block.PrivateComment),
+		PublicComment:  optionalString(block.PublicComment),
+		RejectMedia:    types.Bool{Value: block.RejectMedia},
+		RejectReports:  types.Bool{Value: block.RejectReports},
+	}
+
+	return data.params()
+}
+
+// domainBlocksChange is a change to a single domain block.
+type domainBlocksChange struct {
+	action string
+	domain string
+	method string
+	path   string
+	params url.Values
+}
+
+// applyDomainBlockChanges makes the requests for changes, at most n at a time, returning the error of each
+// failed change.
+func applyDomainBlockChanges(ctx context.Context, client *mastodon.Client, n int, changes []domainBlocksChange) []error {
+	errs := make([]error, len(changes))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, n)
+	for i := range changes {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			change := changes[i]
+			_, err := doAPI(ctx, client, change.method, change.path, change.params, nil)
+			if err != nil && !(change.method == http.MethodDelete && isNotFound(err)) {
+				errs[i] = err
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	return errs
+}
+
+type domainBlocksResource struct {
+	provider mastodonProvider
+}
+
+func (r domainBlocksResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data domainBlocksResourceData
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() || data.Blocks.IsUnknown() {
+		return
+	}
+
+	// the server only allows one block per domain
+	seen := map[string]bool{}
+	for _, elem := range data.Blocks.Elems {
+		obj, ok := elem.(types.Object)
+		if !ok || obj.IsUnknown() {
+			continue
+		}
+
+		domain, ok := obj.Attrs["domain"].(types.String)
+		if !ok || domain.IsNull() || domain.IsUnknown() {
+			continue
+		}
+
+		key := strings.ToLower(domain.Value)
+		if seen[key] {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("blocks"),
+				"Duplicate Domain Block",
+				"The domain "+key+" is blocked more than once.",
+			)
+		}
+		seen[key] = true
+	}
+}
+
+func (r domainBlocksResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// nothing to check when destroying
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.provider.requireCapability(capAdminDomainBlocks, "mastodon_domain_blocks")...)
+}
+
+// apply changes the blocks on the server to the planned blocks, and sets the state to the result. Blocks
+// that are in state but no longer planned are deleted.
+func (r domainBlocksResource) apply(ctx context.Context, plan domainBlocksResourceData, state *domainBlocksResourceData, s *tfsdk.State) diag.Diagnostics {
+	client, diags := r.provider.newAdminClient(ctx)
+	if diags.HasError() {
+		return diags
+	}
+
+	current, err := listAll[domainBlock](ctx, client, "/api/v1/admin/domain_blocks", nil)
+	if err != nil {
+		addAPIError(&diags, "list domain blocks", err)
+
+		return diags
+	}
+
+	planned := plan.entries()
+	managed := map[string]types.Object{}
+	if state != nil {
+		managed = state.entries()
+	}
+
+	var changes []domainBlocksChange
+	existing := make(map[string]bool, len(current))
+	for _, block := range current {
+		existing[block.Domain] = true
+
+		entry, ok := planned[block.Domain]
+		if !ok {
+			if _, ok := managed[block.Domain]; ok || plan.Authoritative.Value {
+				changes = append(changes, domainBlocksChange{
+					action: "delete",
+					domain: block.Domain,
+					method: http.MethodDelete,
+					path:   "/api/v1/admin/domain_blocks/" + url.PathEscape(block.ID),
+				})
+			}
+
+			continue
+		}
+
+		if want := domainBlockEntry(entry); !domainBlockEqual(want, block) {
+			changes = append(changes, domainBlocksChange{
+				action: "update",
+				domain: block.Domain,
+				method: http.MethodPut,
+				path:   "/api/v1/admin/domain_blocks/" + url.PathEscape(block.ID),
+				params: domainBlockParams(want),
+			})
+		}
+	}
+
+	for domain, entry := range planned {
+		if existing[domain] {
+			continue
+		}
+
+		params := domainBlockParams(domainBlockEntry(entry))
+		params.Set("domain", domain)
+		changes = append(changes, domainBlocksChange{
+			action: "create",
+			domain: domain,
+			method: http.MethodPost,
+			path:   "/api/v1/admin/domain_blocks",
+			params: params,
+		})
+	}
+
+	tflog.Debug(ctx, "applying domain block changes", map[string]interface{}{"changes": len(changes)})
+
+	failed := false
+	for i, err := range applyDomainBlockChanges(ctx, client, domainBlocksParallelism, changes) {
+		if err != nil {
+			addAPIError(&diags, changes[i].action+" domain block for "+changes[i].domain, err)
+			failed = true
+		}
+	}
+
+	plan.ID = types.String{Value: r.provider.domain}
+
+	// when a change failed the state is set to the blocks actually on the server, so failed deletes stay
+	// managed and failed creates and updates are planned again
+	if failed {
+		current, err := listAll[domainBlock](ctx, client, "/api/v1/admin/domain_blocks", nil)
+		if err != nil {
+			addAPIError(&diags, "list domain blocks", err)
+
+			return diags
+		}
+
+		for domain, entry := range planned {
+			managed[domain] = entry
+		}
+		plan.update(current, managed)
+	}
+
+	diags.Append(s.Set(ctx, &plan)...)
+
+	return diags
+}
+
+func (r domainBlocksResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data domainBlocksResourceData
+
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.apply(ctx, data, nil, &resp.State)...)
+
+	tflog.Trace(ctx, "created a resource")
+}
+
+func (r domainBlocksResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data domainBlocksResourceData
+
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.provider.newAdminClient(ctx)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	blocks, err := listAll[domainBlock](ctx, client, "/api/v1/admin/domain_blocks", nil)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "list domain blocks", err)
+
+		return
+	}
+
+	data.update(blocks, data.entries())
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r domainBlocksResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data, state domainBlocksResourceData
+
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.apply(ctx, data, &state, &resp.State)...)
+}
+
+func (r domainBlocksResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data domainBlocksResourceData
+
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.provider.newAdminClient(ctx)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	blocks, err := listAll[domainBlock](ctx, client, "/api/v1/admin/domain_blocks", nil)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "list domain blocks", err)
+
+		return
+	}
+
+	// only the blocks in state are deleted, even when authoritative, so blocks added since the last
+	// refresh are kept
+	managed := data.entries()
+
+	var changes []domainBlocksChange
+	for _, block := range blocks {
+		if _, ok := managed[block.Domain]; !ok {
+			continue
+		}
+
+		changes = append(changes, domainBlocksChange{
+			action: "delete",
+			domain: block.Domain,
+			method: http.MethodDelete,
+			path:   "/api/v1/admin/domain_blocks/" + url.PathEscape(block.ID),
+		})
+	}
+
+	for i, err := range applyDomainBlockChanges(ctx, client, domainBlocksParallelism, changes) {
+		if err != nil {
+			addAPIError(&resp.Diagnostics, changes[i].action+" domain block for "+changes[i].domain, err)
+		}
+	}
+}
+
+func (r domainBlocksResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// imports aren't authoritative, so blocks on the server are only adopted once they're configured,
+	// or all of them if authoritative is configured
+	data := domainBlocksResourceData{
+		Authoritative: types.Bool{Value: false},
+		Blocks: types.Set{
+			ElemType: types.ObjectType{AttrTypes: domainBlockTypes},
+		},
+		ID: types.String{Value: req.ID},
+	}
+
+	diags := resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,255 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/feditools/terraform-provider-mastodon/internal/fakemastodon"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccDomainBlocksResource(t *testing.T) {
+	s := fakemastodon.New()
+	defer s.Close()
+
+	token := s.AddAdminToken()
+
+	// writes records the domain block writes before a step, so it can check exactly which were sent
+	var writes domainBlockWrites
+
+	// enough blocks to need more than one page when listing
+	for i := 0; i < 150; i++ {
+		s.AddDomainBlock(fakemastodon.DomainBlock{Domain: fmt.Sprintf("unmanaged%d.example", i)})
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(_ *terraform.State) error {
+			if s.DomainBlock("bad.example") != nil || s.DomainBlock("worse.example") != nil {
+				return fmt.Errorf("domain blocks weren't deleted")
+			}
+
+			return nil
+		},
+		Steps: []resource.TestStep{
+			// Create and Read testing, unmanaged blocks are ignored
+			{
+				Config: testAccDomainBlocksResourceConfig(s, token, `
+	blocks = [
+		{ domain = "bad.example" },
+		{ domain = "worse.example", severity = "suspend", reject_media = true },
+	]
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("mastodon_domain_blocks.test", "id"),
+					resource.TestCheckResourceAttr("mastodon_domain_blocks.test", "authoritative", "false"),
+					resource.TestCheckResourceAttr("mastodon_domain_blocks.test", "blocks.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs("mastodon_domain_blocks.test", "blocks.*", map[string]string{
+						"domain":       "worse.example",
+						"severity":     "suspend",
+						"reject_media": "true",
+					}),
+					func(_ *terraform.State) error {
+						if s.DomainBlocks() != 152 {
+							return fmt.Errorf("expected 152 domain blocks, got %d", s.DomainBlocks())
+						}
+						if block := s.DomainBlock("bad.example"); block == nil || block.Severity != "silence" {
+							return fmt.Errorf("expected bad.example to be silenced, got %v", block)
+						}
+
+						return nil
+					},
+				),
+			},
+			// Update and Read testing, only changed blocks are sent
+			{
+				PreConfig: func() { writes = testAccDomainBlockWrites(s) },
+				Config: testAccDomainBlocksResourceConfig(s, token, `
+	blocks = [
+		{ domain = "bad.example", public_comment = "spam" },
+		{ domain = "worse.example", severity = "suspend", reject_media = true },
+	]
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs("mastodon_domain_blocks.test", "blocks.*", map[string]string{
+						"domain":         "bad.example",
+						"public_comment": "spam",
+					}),
+					func(_ *terraform.State) error {
+						if block := s.DomainBlock("bad.example"); block == nil || block.PublicComment == nil || *block.PublicComment != "spam" {
+							return fmt.Errorf("expected bad.example to have a public comment, got %v", block)
+						}
+
+						return nil
+					},
+					testAccCheckDomainBlockWrites(s, &writes, domainBlockWrites{put: 1}),
+				),
+			},
+			// Drift testing, managed block changed and deleted outside of terraform
+			{
+				PreConfig: func() {
+					s.UpdateDomainBlock("worse.example", func(block *fakemastodon.DomainBlock) {
+						block.Severity = "noop"
+					})
+					s.RemoveDomainBlock("bad.example")
+				},
+				Config: testAccDomainBlocksResourceConfig(s, token, `
+	blocks = [
+		{ domain = "bad.example", public_comment = "spam" },
+		{ domain = "worse.example", severity = "suspend", reject_media = true },
+	]
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					func(_ *terraform.State) error {
+						if s.DomainBlock("bad.example") == nil {
+							return fmt.Errorf("expected bad.example to be blocked again")
+						}
+						if block := s.DomainBlock("worse.example"); block == nil || block.Severity != "suspend" {
+							return fmt.Errorf("expected worse.example to be suspended, got %v", block)
+						}
+
+						return nil
+					},
+				),
+			},
+			// Failure testing, a failed delete keeps the block in state and a failed create leaves it out
+			{
+				PreConfig: func() {
+					s.FailUnprocessable(http.MethodDelete, "/api/v1/admin/domain_blocks/"+s.DomainBlock("bad.example").ID, "Domain block is locked")
+					s.FailUnprocessable(http.MethodPost, "/api/v1/admin/domain_blocks", "Domain is invalid")
+				},
+				Config: testAccDomainBlocksResourceConfig(s, token, `
+	blocks = [
+		{ domain = "worse.example", severity = "suspend", reject_media = true },
+		{ domain = "other.example" },
+	]
+`),
+				ExpectError: regexp.MustCompile(`Unable to delete domain block for bad\.example`),
+			},
+			// Failed changes are retried
+			{
+				PreConfig: func() { writes = testAccDomainBlockWrites(s) },
+				Config: testAccDomainBlocksResourceConfig(s, token, `
+	blocks = [
+		{ domain = "worse.example", severity = "suspend", reject_media = true },
+		{ domain = "other.example" },
+	]
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("mastodon_domain_blocks.test", "blocks.#", "2"),
+					func(_ *terraform.State) error {
+						if s.DomainBlock("bad.example") != nil {
+							return fmt.Errorf("expected bad.example to be unblocked")
+						}
+						if s.DomainBlock("other.example") == nil {
+							return fmt.Errorf("expected other.example to be blocked")
+						}
+
+						return nil
+					},
+					testAccCheckDomainBlockWrites(s, &writes, domainBlockWrites{post: 1, delete: 1}),
+				),
+			},
+			// Authoritative testing, unmanaged blocks and blocks removed from the configuration are deleted
+			{
+				Config: testAccDomainBlocksResourceConfig(s, token, `
+	authoritative = true
+	blocks = [
+		{ domain = "worse.example", severity = "suspend", reject_media = true },
+	]
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("mastodon_domain_blocks.test", "blocks.#", "1"),
+					func(_ *terraform.State) error {
+						if s.DomainBlocks() != 1 {
+							return fmt.Errorf("expected 1 domain block, got %d", s.DomainBlocks())
+						}
+
+						return nil
+					},
+				),
+			},
+			// Drift testing, authoritative blocks added outside of terraform are removed
+			{
+				PreConfig: func() {
+					s.AddDomainBlock(fakemastodon.DomainBlock{Domain: "new.example"})
+				},
+				Config: testAccDomainBlocksResourceConfig(s, token, `
+	authoritative = true
+	blocks = [
+		{ domain = "worse.example", severity = "suspend", reject_media = true },
+	]
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					func(_ *terraform.State) error {
+						if s.DomainBlock("new.example") != nil {
+							return fmt.Errorf("expected new.example to be unblocked")
+						}
+
+						return nil
+					},
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "mastodon_domain_blocks.test",
+				ImportState:       true,
+				ImportStateId:     s.Domain(),
+				ImportStateVerify: true,
+				// imports aren't authoritative, so no blocks are adopted until the configuration is applied
+				ImportStateVerifyIgnore: []string{"authoritative", "blocks"},
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if len(states) != 1 || states[0].Attributes["authoritative"] != "false" || states[0].Attributes["blocks.#"] != "0" {
+						return fmt.Errorf("expected a non-authoritative import without blocks, got %v", states)
+					}
+
+					return nil
+				},
+			},
+		},
+	})
+}
+
+const testAccDomainBlocksResourceConfigTmpl = `
+resource "mastodon_domain_blocks" "test" {
+%[1]s}
+`
+
+func testAccDomainBlocksResourceConfig(s *fakemastodon.Server, token, attributes string) string {
+	return testAccProviderConfigWithToken(s, token) + fmt.Sprintf(testAccDomainBlocksResourceConfigTmpl, attributes)
+}
+
+// domainBlockWrites is the number of domain block writes by method.
+type domainBlockWrites struct {
+	post, put, delete int
+}
+
+func testAccDomainBlockWrites(s *fakemastodon.Server) domainBlockWrites {
+	return domainBlockWrites{
+		post:   s.RequestsMatching(http.MethodPost, "/api/v1/admin/domain_blocks"),
+		put:    s.RequestsMatching(http.MethodPut, "/api/v1/admin/domain_blocks"),
+		delete: s.RequestsMatching(http.MethodDelete, "/api/v1/admin/domain_blocks"),
+	}
+}
+
+// testAccCheckDomainBlockWrites checks the server received exactly want domain block writes since before
+// was recorded.
+func testAccCheckDomainBlockWrites(s *fakemastodon.Server, before *domainBlockWrites, want domainBlockWrites) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		after := testAccDomainBlockWrites(s)
+		got := domainBlockWrites{
+			post:   after.post - before.post,
+			put:    after.put - before.put,
+			delete: after.delete - before.delete,
+		}
+		if got != want {
+			return fmt.Errorf("expected %+v domain block writes, got %+v", want, got)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,305 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ provider.ResourceType = emailDomainBlockResourceType{}
+var _ resource.Resource = emailDomainBlockResource{}
+var _ resource.ResourceWithModifyPlan = emailDomainBlockResource{}
+var _ resource.ResourceWithImportState = emailDomainBlockResource{}
+
+// emailDomainBlockHistoryTypes are the attribute types of the entries of history.
+var emailDomainBlockHistoryTypes = map[string]attr.Type{
+	"accounts": types.Int64Type,
+	"day":      types.StringType,
+	"uses":     types.Int64Type,
+}
+
+type emailDomainBlockResourceType struct{}
+
+func (t emailDomainBlockResourceType) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Email Domain Block. Prevents sign ups with email addresses from a domain, requires an access token with the `admin:read:email_domain_blocks` and `admin:write:email_domain_blocks` scopes. Blocks can't be changed once created, so changing the domain creates a new block. The blocks Mastodon's web interface can add for a domain's MX records aren't available, since the API neither creates them nor reports a block's parent or children.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			// inputs
+			"domain": {
+				MarkdownDescription: "Email domain to block",
+				Required:            true,
+				Type:                types.StringType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.RequiresReplace(),
+				},
+			},
+
+			// outputs
+			"created_at": {
+				MarkdownDescription: "When the domain was blocked",
+				Type:                types.StringType,
+				Computed:            true,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
+			},
+			"history": {
+				MarkdownDescription: "Daily counts of attempted sign ups using the domain, most recent first",
+				Computed:            true,
+				Type: types.ListType{
+					ElemType: types.ObjectType{
+						AttrTypes: emailDomainBlockHistoryTypes,
+					},
+				},
+			},
+			"id": {
+				MarkdownDescription: "identifier",
+				Type:                types.StringType,
+				Computed:            true,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
+			},
+		},
+	}, nil
+}
+
+func (t emailDomainBlockResourceType) NewResource(_ context.Context, in provider.Provider) (resource.Resource, diag.Diagnostics) {
+	prov, diags := convertProviderType(in)
+
+	return emailDomainBlockResource{
+		provider: prov,
+	}, diags
+}
+
+type emailDomainBlockResourceData struct {
+	Domain types.String `tfsdk:"domain"`
+
+	CreatedAt types.String `tfsdk:"created_at"`
+	History   types.List   `tfsdk:"history"`
+	ID        types.String `tfsdk:"id"`
+}
+
+// emailDomainBlock is an email domain block as returned by the admin API.
+type emailDomainBlock struct {
+	ID        string    `json:"id"`
+	Domain    string    `json:"domain"`
+	CreatedAt time.Time `json:"created_at"`
+	History   []struct {
+		Day      string `json:"day"`
+		Accounts string `json:"accounts"`
+		Uses     string `json:"uses"`
+	} `json:"history"`
+}
+
+// update sets the data to the values of block.
+func (d *emailDomainBlockResourceData) update(block *emailDomainBlock) {
+	d.CreatedAt = types.String{Value: block.CreatedAt.Format(time.RFC3339)}
+	// the server lowercases domains, so a configured domain that only differs in case is kept
+	if !strings.EqualFold(d.Domain.Value, block.Domain) {
+		d.Domain = types.String{Value: block.Domain}
+	}
+	d.ID = types.String{Value: block.ID}
+
+	d.History = types.List{
+		ElemType: types.ObjectType{AttrTypes: emailDomainBlockHistoryTypes},
+		Elems:    make([]attr.Value, 0, len(block.History)),
+	}
+	for _, h := range block.History {
+		// counts are returned as strings
+		accounts, _ := strconv.ParseInt(h.Accounts, 10, 64)
+		uses, _ := strconv.ParseInt(h.Uses, 10, 64)
+
+		d.History.Elems = append(d.History.Elems, types.Object{
+			AttrTypes: emailDomainBlockHistoryTypes,
+			Attrs: map[string]attr.Value{
+				"accounts": types.Int64{Value: accounts},
+				"day":      types.String{Value: h.Day},
+				"uses":     types.Int64{Value: uses},
+			},
+		})
+	}
+}
+
+type emailDomainBlockResource struct {
+	provider mastodonProvider
+}
+
+func (r emailDomainBlockResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// nothing to check when destroying
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.provider.requireCapability(capAdminEmailDomainBlocks, "mastodon_email_domain_block")...)
+}
+
+func (r emailDomainBlockResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data emailDomainBlockResourceData
+
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.provider.newAdminClient(ctx)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	params := url.Values{
+		"domain": {data.Domain.Value},
+	}
+
+	var block emailDomainBlock
+	_, err := doAPI(ctx, client, http.MethodPost, "/api/v1/admin/email_domain_blocks", params, &block)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "create email domain block", err)
+
+		return
+	}
+
+	data.update(&block)
+
+	tflog.Trace(ctx, "created a resource")
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r emailDomainBlockResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data emailDomainBlockResourceData
+
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.provider.newAdminClient(ctx)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var block emailDomainBlock
+	_, err := doAPI(ctx, client, http.MethodGet, "/api/v1/admin/email_domain_blocks/"+url.PathEscape(data.ID.Value), nil, &block)
+	if err != nil {
+		if isNotFound(err) {
+			resp.State.RemoveResource(ctx)
+
+			return
+		}
+
+		addAPIError(&resp.Diagnostics, "read email domain block", err)
+
+		return
+	}
+
+	data.update(&block)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r emailDomainBlockResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data emailDomainBlockResourceData
+
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	var state emailDomainBlockResourceData
+
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// the api can't update blocks and the domain requires replacement, so the block is unchanged
+	data.CreatedAt = state.CreatedAt
+	data.History = state.History
+	data.ID = state.ID
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r emailDomainBlockResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data emailDomainBlockResourceData
+
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.provider.newAdminClient(ctx)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, err := doAPI(ctx, client, http.MethodDelete, "/api/v1/admin/email_domain_blocks/"+url.PathEscape(data.ID.Value), nil, nil)
+	if err != nil && !isNotFound(err) {
+		addAPIError(&resp.Diagnostics, "delete email domain block", err)
+	}
+}
+
+func (r emailDomainBlockResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	client, diags := r.provider.newAdminClient(ctx)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// the api can't look blocks up by domain, so search the full list
+	blocks, err := listAll[emailDomainBlock](ctx, client, "/api/v1/admin/email_domain_blocks", nil)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "list email domain blocks", err)
+
+		return
+	}
+
+	domain := strings.ToLower(strings.TrimSpace(req.ID))
+	for i := range blocks {
+		if blocks[i].Domain != domain {
+			continue
+		}
+
+		var data emailDomainBlockResourceData
+		data.update(&blocks[i])
+
+		diags = resp.State.Set(ctx, &data)
+		resp.Diagnostics.Append(diags...)
+
+		return
+	}
+
+	resp.Diagnostics.AddError(
+		"Email Domain Block Not Found",
+		"No email domain block exists for "+req.ID+".",
+	)
+}
@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/feditools/terraform-provider-mastodon/internal/fakemastodon"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccEmailDomainBlockResource(t *testing.T) {
+	s := fakemastodon.New()
+	defer s.Close()
+
+	token := s.AddAdminToken()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(_ *terraform.State) error {
+			for _, domain := range []string{"spam.example", "throwaway.example"} {
+				if s.EmailDomainBlock(domain) != nil {
+					return fmt.Errorf("email domain block for %s wasn't deleted", domain)
+				}
+			}
+
+			return nil
+		},
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccEmailDomainBlockResourceConfig(s, token, "spam.example"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("mastodon_email_domain_block.test", "id"),
+					resource.TestCheckResourceAttrSet("mastodon_email_domain_block.test", "created_at"),
+					resource.TestCheckResourceAttr("mastodon_email_domain_block.test", "domain", "spam.example"),
+					resource.TestCheckResourceAttr("mastodon_email_domain_block.test", "history.#", "7"),
+					resource.TestCheckResourceAttr("mastodon_email_domain_block.test", "history.0.uses", "0"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "mastodon_email_domain_block.test",
+				ImportState:       true,
+				ImportStateId:     "spam.example",
+				ImportStateVerify: true,
+			},
+			// Replace testing
+			{
+				Config: testAccEmailDomainBlockResourceConfig(s, token, "throwaway.example"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("mastodon_email_domain_block.test", "domain", "throwaway.example"),
+					func(_ *terraform.State) error {
+						if s.EmailDomainBlock("spam.example") != nil {
+							return fmt.Errorf("replaced email domain block wasn't deleted")
+						}
+
+						return nil
+					},
+				),
+			},
+			// Drift testing, block deleted outside of terraform
+			{
+				PreConfig: func() {
+					s.RemoveEmailDomainBlock("throwaway.example")
+				},
+				Config: testAccEmailDomainBlockResourceConfig(s, token, "throwaway.example"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					func(_ *terraform.State) error {
+						if s.EmailDomainBlock("throwaway.example") == nil {
+							return fmt.Errorf("email domain block wasn't recreated")
+						}
+
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+const testAccEmailDomainBlockResourceConfigTmpl = `
+resource "mastodon_email_domain_block" "test" {
+	domain = %[1]q
+}
+`
+
+func testAccEmailDomainBlockResourceConfig(s *fakemastodon.Server, token, domain string) string {
+	return testAccProviderConfigWithToken(s, token) + fmt.Sprintf(testAccEmailDomainBlockResourceConfigTmpl, domain)
+}
@@ -0,0 +1,131 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"io"
+	"net/http"
+)
+
+// maxErrorBodySize limits how much of an error response body is read.
+const maxErrorBodySize = 64 * 1024
+
+var (
+	errUnauthorized  = errors.New("unauthorized")
+	errForbidden     = errors.New("forbidden")
+	errNotFound      = errors.New("not found")
+	errGone          = errors.New("gone")
+	errUnprocessable = errors.New("unprocessable entity")
+	errRateLimited   = errors.New("rate limited")
+	errServer        = errors.New("server error")
+)
+
+// apiError is returned for any unsuccessful response from the Mastodon API. It matches the
+// sentinel error for its status code with errors.Is.
+type apiError struct {
+	StatusCode  int
+	Status      string
+	Method      string
+	Path        string
+	Message     string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e *apiError) Error() string {
+	msg := e.Status
+	if e.Message != "" {
+		msg = msg + ": " + e.Message
+	}
+	if e.Description != "" {
+		msg = msg + ": " + e.Description
+	}
+
+	return msg
+}
+
+func (e *apiError) Is(target error) bool {
+	switch e.StatusCode {
+	case http.StatusUnauthorized:
+		return target == errUnauthorized
+	case http.StatusForbidden:
+		return target == errForbidden
+	case http.StatusNotFound:
+		return target == errNotFound
+	case http.StatusGone:
+		return target == errGone
+	case http.StatusUnprocessableEntity:
+		return target == errUnprocessable
+	case http.StatusTooManyRequests:
+		return target == errRateLimited
+	}
+
+	return e.StatusCode >= 500 && target == errServer
+}
+
+// checkResponse returns an *apiError for an unsuccessful response, consuming and closing its body.
+// Successful responses are left to the caller.
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode < 400 {
+		return nil
+	}
+
+	return newAPIError(resp)
+}
+
+// newAPIError builds an apiError from resp, consuming and closing its body.
+func newAPIError(resp *http.Response) *apiError {
+	defer resp.Body.Close()
+
+	e := &apiError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+	}
+	if resp.Request != nil {
+		e.Method = resp.Request.Method
+		e.Path = resp.Request.URL.Path
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	if err == nil {
+		_ = json.Unmarshal(body, e)
+	}
+
+	return e
+}
+
+// isNotFound returns true if the error indicates the requested entity doesn't exist.
+func isNotFound(err error) bool {
+	return errors.Is(err, errNotFound) || errors.Is(err, errGone)
+}
+
+// addAPIError adds an error diagnostic describing a failed attempt to perform action.
+func addAPIError(diags *diag.Diagnostics, action string, err error) {
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to %s, got error: %s", action, err))
+
+		return
+	}
+
+	summary := "Client Error"
+	switch {
+	case errors.Is(apiErr, errUnauthorized):
+		summary = "Unauthorized"
+	case errors.Is(apiErr, errForbidden):
+		summary = "Forbidden"
+	case errors.Is(apiErr, errNotFound):
+		summary = "Not Found"
+	case errors.Is(apiErr, errGone):
+		summary = "Gone"
+	case errors.Is(apiErr, errUnprocessable):
+		summary = "Validation Failed"
+	case errors.Is(apiErr, errRateLimited):
+		summary = "Rate Limited"
+	case errors.Is(apiErr, errServer):
+		summary = "Server Error"
+	}
+
+	diags.AddError(summary, fmt.Sprintf("Unable to %s, server responded to %s %s with: %s", action, apiErr.Method, apiErr.Path, apiErr))
+}
@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDoAPIErrors(t *testing.T) {
+	tables := []struct {
+		status  int
+		body    string
+		target  error
+		message string
+	}{
+		{http.StatusUnauthorized, `{"error":"invalid_client","error_description":"Client authentication failed"}`, errUnauthorized, "401 Unauthorized: invalid_client: Client authentication failed"},
+		{http.StatusForbidden, `{"error":"This action is not allowed"}`, errForbidden, "403 Forbidden: This action is not allowed"},
+		{http.StatusNotFound, `{"error":"Record not found"}`, errNotFound, "404 Not Found: Record not found"},
+		{http.StatusGone, ``, errGone, "410 Gone"},
+		{http.StatusUnprocessableEntity, `{"error":"Validation failed: Domain is invalid"}`, errUnprocessable, "422 Unprocessable Entity: Validation failed: Domain is invalid"},
+		{http.StatusTooManyRequests, `{"error":"Too many requests"}`, errRateLimited, "429 Too Many Requests: Too many requests"},
+		{http.StatusServiceUnavailable, `<html></html>`, errServer, "503 Service Unavailable"},
+	}
+
+	for i, table := range tables {
+		i := i
+		table := table
+
+		t.Run(fmt.Sprintf("[%d] %d", i, table.status), func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(table.status)
+				fmt.Fprint(w, table.body)
+			}))
+			defer ts.Close()
+
+			p := &mastodonProvider{
+				domain:    strings.TrimPrefix(ts.URL, "http://"),
+				schema:    "http",
+				transport: http.DefaultTransport,
+			}
+
+			_, err := doAPI(context.Background(), p.newClient(), http.MethodGet, "/api/v1/accounts/1", nil, nil)
+			if !errors.Is(err, table.target) {
+				t.Fatalf("[%d] expected error to match %q, got: %v", i, table.target, err)
+			}
+
+			var apiErr *apiError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("[%d] expected *apiError, got: %T", i, err)
+			}
+			if apiErr.Error() != table.message {
+				t.Errorf("[%d] unexpected message, got: %q, want: %q", i, apiErr.Error(), table.message)
+			}
+			if apiErr.Path != "/api/v1/accounts/1" {
+				t.Errorf("[%d] unexpected path, got: %q, want: %q", i, apiErr.Path, "/api/v1/accounts/1")
+			}
+		})
+	}
+}
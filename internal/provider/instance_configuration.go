@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/mattn/go-mastodon"
+	"net/http"
+	"strings"
+)
+
+// defaultMaxProfileFields is the number of profile fields assumed when the instance reports neither
+// max_profile_fields nor max_fields, which is Mastodon's default limit.
+const defaultMaxProfileFields = 4
+
+// instanceConfiguration is the part of the instance information describing the server's limits.
+type instanceConfiguration struct {
+	Configuration struct {
+		Accounts struct {
+			MaxProfileFields int `json:"max_profile_fields"`
+		} `json:"accounts"`
+		MediaAttachments struct {
+			ImageSizeLimit     int64    `json:"image_size_limit"`
+			SupportedMimeTypes []string `json:"supported_mime_types"`
+		} `json:"media_attachments"`
+	} `json:"configuration"`
+
+	// Pleroma and Akkoma report their limits separately
+	Pleroma struct {
+		Metadata struct {
+			FieldsLimits struct {
+				MaxFields int `json:"max_fields"`
+			} `json:"fields_limits"`
+		} `json:"metadata"`
+	} `json:"pleroma"`
+}
+
+// maxProfileFields returns the number of profile fields an account can have.
+func (c *instanceConfiguration) maxProfileFields() int {
+	if n := c.Configuration.Accounts.MaxProfileFields; n > 0 {
+		return n
+	}
+	if n := c.Pleroma.Metadata.FieldsLimits.MaxFields; n > 0 {
+		return n
+	}
+
+	return defaultMaxProfileFields
+}
+
+// checkImage returns an error if the server doesn't accept an image of contentType and size. Limits the
+// server doesn't report aren't checked.
+func (c *instanceConfiguration) checkImage(contentType string, size int64) error {
+	limits := c.Configuration.MediaAttachments
+
+	// the supported types include video, which can't be used for avatars and headers
+	if !strings.HasPrefix(contentType, "image/") {
+		return fmt.Errorf("the file is of type %s, not an image", contentType)
+	}
+
+	if limits.ImageSizeLimit > 0 && size > limits.ImageSizeLimit {
+		return fmt.Errorf("the image is %d bytes, the server allows at most %d bytes", size, limits.ImageSizeLimit)
+	}
+
+	if len(limits.SupportedMimeTypes) == 0 {
+		return nil
+	}
+	for _, mimeType := range limits.SupportedMimeTypes {
+		if mimeType == contentType {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("the server doesn't support images of type %s", contentType)
+}
+
+// getInstanceConfiguration reads the server's limits, preferring the v2 instance endpoint.
+func getInstanceConfiguration(ctx context.Context, client *mastodon.Client) (*instanceConfiguration, error) {
+	var config instanceConfiguration
+	_, err := doAPI(ctx, client, http.MethodGet, "/api/v2/instance", nil, &config)
+	if err == nil {
+		return &config, nil
+	}
+	if !isNotFound(err) {
+		return nil, err
+	}
+
+	// older servers only have the v1 endpoint, which has the same configuration
+	config = instanceConfiguration{}
+	_, err = doAPI(ctx, client, http.MethodGet, "/api/v1/instance", nil, &config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
@@ -2,13 +2,14 @@ package provider
 
 import (
 	"context"
-	"fmt"
+	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/mattn/go-mastodon"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces
@@ -89,9 +90,10 @@ func (d instanceSelfDataSource) Read(ctx context.Context, req datasource.ReadReq
 		return
 	}
 
-	instance, err := d.provider.newUnauthenticatedClient().GetInstance(ctx)
+	var instance mastodon.Instance
+	_, err := doAPI(ctx, d.provider.newClient(), http.MethodGet, "/api/v1/instance", nil, &instance)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read instance data, got error: %s", err))
+		addAPIError(&resp.Diagnostics, "read instance data", err)
 
 		return
 	}
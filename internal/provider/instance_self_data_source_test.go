@@ -1,34 +1,33 @@
 package provider
 
 import (
-	"fmt"
-	"net/http"
-	"net/http/httptest"
-	"strings"
 	"testing"
 
+	"github.com/feditools/terraform-provider-mastodon/internal/fakemastodon"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 )
 
 func TestAccInstanceSelfDataSource(t *testing.T) {
-	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		fmt.Fprintln(w, `{"email":"user@example.com","thumbnail":"https://example.com/image.jpg","title":"Example Title","uri":"example.com","version":"1.2.4"}`)
-		return
-	}))
-	defer ts.Close()
+	s := fakemastodon.New()
+	defer s.Close()
+
+	s.Instance.Email = "user@example.com"
+	s.Instance.Thumbnail = "https://example.com/image.jpg"
+	s.Instance.Title = "Example Title"
+	s.Instance.Version = "1.2.4"
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { testAccPreCheck(t) },
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccInstanceSelfDataSourceConfig(ts.URL),
+				Config: testAccInstanceSelfDataSourceConfig(s),
 				Check: resource.ComposeAggregateTestCheckFunc(
-					resource.TestCheckResourceAttr("data.mastodon_instance_self.test", "id", "example.com"),
+					resource.TestCheckResourceAttr("data.mastodon_instance_self.test", "id", s.Domain()),
 					resource.TestCheckResourceAttr("data.mastodon_instance_self.test", "email", "user@example.com"),
 					resource.TestCheckResourceAttr("data.mastodon_instance_self.test", "thumbnail", "https://example.com/image.jpg"),
 					resource.TestCheckResourceAttr("data.mastodon_instance_self.test", "title", "Example Title"),
-					resource.TestCheckResourceAttr("data.mastodon_instance_self.test", "uri", "example.com"),
+					resource.TestCheckResourceAttr("data.mastodon_instance_self.test", "uri", s.Domain()),
 					resource.TestCheckResourceAttr("data.mastodon_instance_self.test", "version", "1.2.4"),
 				),
 			},
@@ -36,16 +35,11 @@ func TestAccInstanceSelfDataSource(t *testing.T) {
 	})
 }
 
-const testAccInstanceSelfDataSourceConfigTmplPre = `
-provider "mastodon" {
-	domain = %[1]q
-	use_https = false
-}
-
+const testAccInstanceSelfDataSourceConfigTmpl = `
 data "mastodon_instance_self" "test" {
 }
 `
 
-func testAccInstanceSelfDataSourceConfig(tsURL string) string {
-	return fmt.Sprintf(testAccInstanceSelfDataSourceConfigTmplPre, strings.TrimPrefix(tsURL, "http://"))
+func testAccInstanceSelfDataSourceConfig(s *fakemastodon.Server) string {
+	return testAccProviderConfig(s) + testAccInstanceSelfDataSourceConfigTmpl
 }
@@ -0,0 +1,386 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ provider.ResourceType = ipBlockResourceType{}
+var _ resource.Resource = ipBlockResource{}
+var _ resource.ResourceWithModifyPlan = ipBlockResource{}
+var _ resource.ResourceWithImportState = ipBlockResource{}
+
+const (
+	ipBlockSeveritySignUpRequiresApproval = "sign_up_requires_approval"
+	ipBlockSeveritySignUpBlock            = "sign_up_block"
+	ipBlockSeverityNoAccess               = "no_access"
+)
+
+type ipBlockResourceType struct{}
+
+func (t ipBlockResourceType) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "IP Block. Limits sign ups and access from an IP range, requires an access token with the `admin:read:ip_blocks` and `admin:write:ip_blocks` scopes.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			// inputs
+			"comment": {
+				MarkdownDescription: "Reason for the block",
+				Optional:            true,
+				Type:                types.StringType,
+			},
+			"expires_in": {
+				MarkdownDescription: "Number of seconds after which the block expires, counted from when it's created or this value is changed. Once expired the server deletes the block, and it's created again on the next apply. Defaults to never expiring.",
+				Optional:            true,
+				Type:                types.Int64Type,
+			},
+			"ip": {
+				MarkdownDescription: "IP address or CIDR range to block",
+				Required:            true,
+				Type:                types.StringType,
+				Validators: []tfsdk.AttributeValidator{
+					ipOrCIDR(),
+				},
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.RequiresReplace(),
+				},
+			},
+			"severity": {
+				MarkdownDescription: "Severity of the block, one of `sign_up_requires_approval`, `sign_up_block` or `no_access`",
+				Required:            true,
+				Type:                types.StringType,
+				Validators: []tfsdk.AttributeValidator{
+					stringOneOf(ipBlockSeveritySignUpRequiresApproval, ipBlockSeveritySignUpBlock, ipBlockSeverityNoAccess),
+				},
+			},
+
+			// outputs
+			"expires_at": {
+				MarkdownDescription: "When the block expires",
+				Type:                types.StringType,
+				Computed:            true,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
+			},
+			"id": {
+				MarkdownDescription: "identifier",
+				Type:                types.StringType,
+				Computed:            true,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
+			},
+		},
+	}, nil
+}
+
+func (t ipBlockResourceType) NewResource(_ context.Context, in provider.Provider) (resource.Resource, diag.Diagnostics) {
+	prov, diags := convertProviderType(in)
+
+	return ipBlockResource{
+		provider: prov,
+	}, diags
+}
+
+type ipBlockResourceData struct {
+	Comment   types.String `tfsdk:"comment"`
+	ExpiresIn types.Int64  `tfsdk:"expires_in"`
+	IP        types.String `tfsdk:"ip"`
+	Severity  types.String `tfsdk:"severity"`
+
+	ExpiresAt types.String `tfsdk:"expires_at"`
+	ID        types.String `tfsdk:"id"`
+}
+
+// ipBlock is an IP block as returned by the admin API.
+type ipBlock struct {
+	ID        string     `json:"id"`
+	IP        string     `json:"ip"`
+	Severity  string     `json:"severity"`
+	Comment   string     `json:"comment"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// expired returns true if the block is past its expiry but hasn't been deleted by the server yet.
+func (b *ipBlock) expired() bool {
+	return b.ExpiresAt != nil && b.ExpiresAt.Before(time.Now())
+}
+
+// update sets the data to the values of block. The server returns the network address with its prefix
+// length, so the configured form of the same range is kept to avoid a diff.
+func (d *ipBlockResourceData) update(block *ipBlock) {
+	configured, _ := parseIPOrCIDR(d.IP.Value)
+	returned, ok := parseIPOrCIDR(block.IP)
+	if d.IP.IsNull() || d.IP.IsUnknown() || !ok || configured.Masked() != returned.Masked() {
+		d.IP = types.String{Value: block.IP}
+	}
+
+	d.Comment = optionalString(&block.Comment)
+	d.ID = types.String{Value: block.ID}
+	d.Severity = types.String{Value: block.Severity}
+
+	d.ExpiresAt = types.String{Null: true}
+	if block.ExpiresAt != nil {
+		d.ExpiresAt = types.String{Value: block.ExpiresAt.Format(time.RFC3339)}
+	}
+}
+
+// expiresIn returns the expires_in parameter, empty to remove the expiry.
+func (d ipBlockResourceData) expiresIn() string {
+	if d.ExpiresIn.IsNull() {
+		return ""
+	}
+
+	return strconv.FormatInt(d.ExpiresIn.Value, 10)
+}
+
+type ipBlockResource struct {
+	provider mastodonProvider
+}
+
+func (r ipBlockResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// nothing to check when destroying
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.provider.requireCapability(capAdminIPBlocks, "mastodon_ip_block")...)
+
+	// nothing else to plan when creating
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	var plan, state ipBlockResourceData
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// changing expires_in restarts the expiry
+	if !plan.ExpiresIn.Equal(state.ExpiresIn) {
+		diags = resp.Plan.SetAttribute(ctx, path.Root("expires_at"), types.String{Unknown: true})
+		resp.Diagnostics.Append(diags...)
+	}
+}
+
+func (r ipBlockResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data ipBlockResourceData
+
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.provider.newAdminClient(ctx)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	params := url.Values{
+		"ip":       {data.IP.Value},
+		"severity": {data.Severity.Value},
+		"comment":  {data.Comment.Value},
+	}
+	if !data.ExpiresIn.IsNull() {
+		params.Set("expires_in", data.expiresIn())
+	}
+
+	var block ipBlock
+	_, err := doAPI(ctx, client, http.MethodPost, "/api/v1/admin/ip_blocks", params, &block)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "create ip block", err)
+
+		return
+	}
+
+	data.update(&block)
+
+	tflog.Trace(ctx, "created a resource")
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r ipBlockResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data ipBlockResourceData
+
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.provider.newAdminClient(ctx)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var block ipBlock
+	_, err := doAPI(ctx, client, http.MethodGet, "/api/v1/admin/ip_blocks/"+url.PathEscape(data.ID.Value), nil, &block)
+	if err != nil {
+		if isNotFound(err) {
+			resp.State.RemoveResource(ctx)
+
+			return
+		}
+
+		addAPIError(&resp.Diagnostics, "read ip block", err)
+
+		return
+	}
+
+	// expired blocks are only kept until the server's next cleanup
+	if block.expired() {
+		resp.State.RemoveResource(ctx)
+
+		return
+	}
+
+	// expires_in isn't returned, it's only used when the block is created or expires_in changes, so it's
+	// left as configured rather than derived from the remaining time
+	data.update(&block)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r ipBlockResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data ipBlockResourceData
+
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	var state ipBlockResourceData
+
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.provider.newAdminClient(ctx)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	params := url.Values{
+		"severity": {data.Severity.Value},
+		"comment":  {data.Comment.Value},
+	}
+	// sending expires_in restarts the expiry, so only send it when it changed
+	if !data.ExpiresIn.Equal(state.ExpiresIn) {
+		params.Set("expires_in", data.expiresIn())
+	}
+
+	var block ipBlock
+	_, err := doAPI(ctx, client, http.MethodPut, "/api/v1/admin/ip_blocks/"+url.PathEscape(data.ID.Value), params, &block)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "update ip block", err)
+
+		return
+	}
+
+	data.update(&block)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r ipBlockResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data ipBlockResourceData
+
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.provider.newAdminClient(ctx)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, err := doAPI(ctx, client, http.MethodDelete, "/api/v1/admin/ip_blocks/"+url.PathEscape(data.ID.Value), nil, nil)
+	if err != nil && !isNotFound(err) {
+		addAPIError(&resp.Diagnostics, "delete ip block", err)
+	}
+}
+
+func (r ipBlockResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	prefix, ok := parseIPOrCIDR(req.ID)
+	if !ok {
+		resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+
+		return
+	}
+
+	client, diags := r.provider.newAdminClient(ctx)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// the api can't look blocks up by address, so search the full list
+	blocks, err := listAll[ipBlock](ctx, client, "/api/v1/admin/ip_blocks", nil)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "list ip blocks", err)
+
+		return
+	}
+
+	for i := range blocks {
+		if p, ok := parseIPOrCIDR(blocks[i].IP); !ok || p.Masked() != prefix.Masked() {
+			continue
+		}
+
+		data := ipBlockResourceData{
+			IP:        types.String{Value: req.ID},
+			ExpiresIn: types.Int64{Null: true},
+		}
+		data.update(&blocks[i])
+
+		diags = resp.State.Set(ctx, &data)
+		resp.Diagnostics.Append(diags...)
+
+		return
+	}
+
+	resp.Diagnostics.AddError(
+		"IP Block Not Found",
+		"No ip block exists for "+req.ID+".",
+	)
+}
@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/feditools/terraform-provider-mastodon/internal/fakemastodon"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccIPBlockResource(t *testing.T) {
+	s := fakemastodon.New()
+	defer s.Close()
+
+	token := s.AddAdminToken()
+
+	var id string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(_ *terraform.State) error {
+			if s.IPBlock("192.0.2.1/32") != nil {
+				return fmt.Errorf("ip block wasn't deleted")
+			}
+
+			return nil
+		},
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccIPBlockResourceConfig(s, token, `
+	severity = "sign_up_requires_approval"
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("mastodon_ip_block.test", "id"),
+					resource.TestCheckResourceAttr("mastodon_ip_block.test", "ip", "192.0.2.1"),
+					resource.TestCheckResourceAttr("mastodon_ip_block.test", "severity", "sign_up_requires_approval"),
+					resource.TestCheckNoResourceAttr("mastodon_ip_block.test", "comment"),
+					resource.TestCheckNoResourceAttr("mastodon_ip_block.test", "expires_at"),
+					testAccCheckResourceID("mastodon_ip_block.test", &id),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "mastodon_ip_block.test",
+				ImportState:       true,
+				ImportStateId:     "192.0.2.1",
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: testAccIPBlockResourceConfig(s, token, `
+	severity   = "no_access"
+	comment    = "brute force"
+	expires_in = 3600
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("mastodon_ip_block.test", "severity", "no_access"),
+					resource.TestCheckResourceAttr("mastodon_ip_block.test", "comment", "brute force"),
+					resource.TestCheckResourceAttr("mastodon_ip_block.test", "expires_in", "3600"),
+					resource.TestCheckResourceAttrSet("mastodon_ip_block.test", "expires_at"),
+					testAccCheckResourceID("mastodon_ip_block.test", &id),
+				),
+			},
+			// Expiry testing, block expired and deleted by the server
+			{
+				PreConfig: func() {
+					s.UpdateIPBlock("192.0.2.1/32", func(block *fakemastodon.IPBlock) {
+						expiresAt := time.Now().Add(-time.Minute)
+						block.ExpiresAt = &expiresAt
+					})
+				},
+				Config: testAccIPBlockResourceConfig(s, token, `
+	severity   = "no_access"
+	comment    = "brute force"
+	expires_in = 3600
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("mastodon_ip_block.test", "expires_at"),
+					func(_ *terraform.State) error {
+						block := s.IPBlock("192.0.2.1/32")
+						if block == nil {
+							return fmt.Errorf("expired ip block wasn't recreated")
+						}
+						if block.ID == id {
+							return fmt.Errorf("expected a new ip block, got: %s", block.ID)
+						}
+
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+const testAccIPBlockResourceConfigTmpl = `
+resource "mastodon_ip_block" "test" {
+	ip = "192.0.2.1"
+%[1]s}
+`
+
+func testAccIPBlockResourceConfig(s *fakemastodon.Server, token, attributes string) string {
+	return testAccProviderConfigWithToken(s, token) + fmt.Sprintf(testAccIPBlockResourceConfigTmpl, attributes)
+}
@@ -0,0 +1,154 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"io"
+	"mime"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// maxLoggedBodySize limits how much of a request or response body is logged.
+const maxLoggedBodySize = 16 * 1024
+
+// loggedSecretKeys are the field keys whose values are masked in log entries.
+var loggedSecretKeys = []string{
+	"access_token",
+	"authorization",
+	"client_secret",
+	"password",
+	"token",
+}
+
+// loggedSecretRegexes match secrets inside JSON and form encoded bodies.
+var loggedSecretRegexes = []*regexp.Regexp{
+	regexp.MustCompile(`"(access_token|client_secret|password|token)"\s*:\s*"[^"]*"`),
+	regexp.MustCompile(`\b(access_token|client_secret|password|token)=[^&\s]*`),
+}
+
+// loggingTransport logs every request and its response through tflog. Each exchange is logged
+// at DEBUG, the bodies of text based requests and responses are logged at TRACE.
+type loggingTransport struct {
+	base http.RoundTripper
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := maskLogSecrets(req.Context())
+
+	fields := map[string]interface{}{
+		"http_method": req.Method,
+		"http_path":   req.URL.Path,
+	}
+
+	if body, ok := loggableRequestBody(req); ok {
+		tflog.Trace(ctx, "sending request body", mergeLogFields(fields, map[string]interface{}{
+			"http_request_body": body,
+		}))
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	fields["duration_ms"] = time.Since(start).Milliseconds()
+
+	if err != nil {
+		tflog.Debug(ctx, "request failed", mergeLogFields(fields, map[string]interface{}{
+			"error": err.Error(),
+		}))
+
+		return nil, err
+	}
+
+	fields["http_status"] = resp.StatusCode
+	for _, header := range []string{"X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset"} {
+		if v := resp.Header.Get(header); v != "" {
+			fields[strings.ToLower(strings.ReplaceAll(header, "-", "_"))] = v
+		}
+	}
+
+	tflog.Debug(ctx, "received response", fields)
+
+	if isLoggableContentType(resp.Header.Get("Content-Type")) {
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+
+		tflog.Trace(ctx, "received response body", mergeLogFields(fields, map[string]interface{}{
+			"http_response_body": truncateLoggedBody(body),
+		}))
+	}
+
+	return resp, nil
+}
+
+// maskLogSecrets returns a context whose log entries have credentials masked.
+func maskLogSecrets(ctx context.Context) context.Context {
+	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, loggedSecretKeys...)
+	ctx = tflog.MaskAllFieldValuesRegexes(ctx, loggedSecretRegexes...)
+	ctx = tflog.MaskMessageRegexes(ctx, loggedSecretRegexes...)
+
+	return ctx
+}
+
+// loggableRequestBody returns a copy of the request body if it's text based and can be read
+// without consuming it.
+func loggableRequestBody(req *http.Request) (string, bool) {
+	if req.Body == nil || req.GetBody == nil || !isLoggableContentType(req.Header.Get("Content-Type")) {
+		return "", false
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return "", false
+	}
+	defer body.Close()
+
+	b, err := io.ReadAll(io.LimitReader(body, maxLoggedBodySize+1))
+	if err != nil {
+		return "", false
+	}
+
+	return truncateLoggedBody(b), true
+}
+
+func isLoggableContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	switch {
+	case mediaType == "application/json",
+		mediaType == "application/x-www-form-urlencoded",
+		strings.HasSuffix(mediaType, "+json"),
+		strings.HasPrefix(mediaType, "text/"):
+		return true
+	}
+
+	return false
+}
+
+func truncateLoggedBody(body []byte) string {
+	if len(body) > maxLoggedBodySize {
+		return string(body[:maxLoggedBodySize]) + "...(truncated)"
+	}
+
+	return string(body)
+}
+
+func mergeLogFields(fields ...map[string]interface{}) map[string]interface{} {
+	merged := map[string]interface{}{}
+	for _, f := range fields {
+		for k, v := range f {
+			merged[k] = v
+		}
+	}
+
+	return merged
+}
@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestLoggingTransport(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Header().Set("X-RateLimit-Remaining", "299")
+		fmt.Fprint(w, `{"access_token":"response-secret","token_type":"Bearer"}`)
+	}))
+	defer ts.Close()
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	form := url.Values{
+		"client_id":     {"client"},
+		"client_secret": {"request-secret"},
+	}
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, ts.URL+"/oauth/token", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "Bearer header-secret")
+
+	client := http.Client{Transport: &loggingTransport{base: http.DefaultTransport}}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer resp.Body.Close()
+
+	var body bytes.Buffer
+	_, _ = body.ReadFrom(resp.Body)
+	if !strings.Contains(body.String(), "response-secret") {
+		t.Errorf("response body wasn't passed through, got: %s", body.String())
+	}
+
+	logged := output.String()
+	for _, secret := range []string{"request-secret", "response-secret", "header-secret"} {
+		if strings.Contains(logged, secret) {
+			t.Errorf("secret %q was logged: %s", secret, logged)
+		}
+	}
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatalf("unable to decode log entries: %s", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("unexpected number of log entries, got: %d, want: %d", len(entries), 3)
+	}
+
+	response := entries[1]
+	if response["http_method"] != http.MethodPost || response["http_path"] != "/oauth/token" {
+		t.Errorf("unexpected request fields, got: %v", response)
+	}
+	if response["http_status"] != float64(http.StatusOK) {
+		t.Errorf("unexpected status field, got: %v", response["http_status"])
+	}
+	if response["x_ratelimit_remaining"] != "299" {
+		t.Errorf("unexpected rate limit field, got: %v", response["x_ratelimit_remaining"])
+	}
+}
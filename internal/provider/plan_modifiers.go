@@ -0,0 +1,36 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+)
+
+// defaultValue returns a plan modifier that plans value for an optional computed attribute
+// that isn't set in the configuration.
+func defaultValue(value attr.Value) tfsdk.AttributePlanModifier {
+	return defaultValueModifier{
+		value: value,
+	}
+}
+
+type defaultValueModifier struct {
+	value attr.Value
+}
+
+func (m defaultValueModifier) Description(_ context.Context) string {
+	return fmt.Sprintf("If not configured, defaults to %s", m.value)
+}
+
+func (m defaultValueModifier) MarkdownDescription(_ context.Context) string {
+	return fmt.Sprintf("If not configured, defaults to `%s`", m.value)
+}
+
+func (m defaultValueModifier) Modify(_ context.Context, req tfsdk.ModifyAttributePlanRequest, resp *tfsdk.ModifyAttributePlanResponse) {
+	if req.AttributeConfig == nil || !req.AttributeConfig.IsNull() {
+		return
+	}
+
+	resp.AttributePlan = m.value
+}
@@ -4,27 +4,50 @@ import (
 	"context"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"sync"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	envAccessToken = "MASTODON_ACCESS_TOKEN"
+	envDomain      = "MASTODON_DOMAIN"
+	envUseHTTPS    = "MASTODON_USE_HTTPS"
 )
 
 var _ provider.Provider = &mastodonProvider{}
 
 type mastodonProvider struct {
-	accessToken     string
-	accessTokenLock *sync.RWMutex
-	domain          string
-	schema          string
+	accessToken string
+	appTokens   *tokenCache
+	domain      string
+	schema      string
+	serverInfo  *serverInfo
+	transport   http.RoundTripper
 
 	configured bool
 	version    string
 }
 
 type providerData struct {
-	Domain   types.String `tfsdk:"domain"`
-	UseHTTPS types.Bool   `tfsdk:"use_https"`
+	AccessToken        types.String `tfsdk:"access_token"`
+	CACertFile         types.String `tfsdk:"ca_cert_file"`
+	CACertPEM          types.String `tfsdk:"ca_cert_pem"`
+	ClientCertPEM      types.String `tfsdk:"client_cert_pem"`
+	ClientKeyPEM       types.String `tfsdk:"client_key_pem"`
+	Domain             types.String `tfsdk:"domain"`
+	InsecureSkipVerify types.Bool   `tfsdk:"insecure_skip_verify"`
+	MaxRetries         types.Int64  `tfsdk:"max_retries"`
+	MaxRetryWait       types.Int64  `tfsdk:"max_retry_wait"`
+	ProxyURL           types.String `tfsdk:"proxy_url"`
+	RequestTimeout     types.Int64  `tfsdk:"request_timeout"`
+	UseHTTPS           types.Bool   `tfsdk:"use_https"`
 }
 
 func (p *mastodonProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
@@ -36,38 +59,230 @@ func (p *mastodonProvider) Configure(ctx context.Context, req provider.Configure
 		return
 	}
 
-	p.accessTokenLock = &sync.RWMutex{}
-	p.domain = data.Domain.Value
+	if data.Domain.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("domain"),
+			"Unknown Mastodon Domain",
+			"The provider cannot create the Mastodon client as there is an unknown configuration value for the domain. "+
+				"Either set the value statically in the configuration, or use the "+envDomain+" environment variable.",
+		)
+	}
+	if data.AccessToken.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("access_token"),
+			"Unknown Mastodon Access Token",
+			"The provider cannot create the Mastodon client as there is an unknown configuration value for the access token. "+
+				"Either set the value statically in the configuration, or use the "+envAccessToken+" environment variable.",
+		)
+	}
+	if data.UseHTTPS.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("use_https"),
+			"Unknown Mastodon Use HTTPS",
+			"The provider cannot create the Mastodon client as there is an unknown configuration value for use_https. "+
+				"Either set the value statically in the configuration, or use the "+envUseHTTPS+" environment variable.",
+		)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// config values take precedence over the environment
+	domain := os.Getenv(envDomain)
+	if !data.Domain.IsNull() {
+		domain = data.Domain.Value
+	}
+
+	accessToken := os.Getenv(envAccessToken)
+	if !data.AccessToken.IsNull() {
+		accessToken = data.AccessToken.Value
+	}
+
+	useHTTPS := true
+	if v := os.Getenv(envUseHTTPS); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("use_https"),
+				"Invalid Mastodon Use HTTPS",
+				fmt.Sprintf("The %s environment variable must be a boolean, got %q.", envUseHTTPS, v),
+			)
+		}
+		useHTTPS = b
+	}
+	if !data.UseHTTPS.IsNull() {
+		useHTTPS = data.UseHTTPS.Value
+	}
+
+	maxRetries := defaultMaxRetries
+	if !data.MaxRetries.IsNull() && !data.MaxRetries.IsUnknown() {
+		if data.MaxRetries.Value < 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("max_retries"),
+				"Invalid Max Retries",
+				fmt.Sprintf("max_retries must not be negative, got %d.", data.MaxRetries.Value),
+			)
+		}
+		maxRetries = int(data.MaxRetries.Value)
+	}
+
+	maxRetryWait := defaultMaxRetryWait
+	if !data.MaxRetryWait.IsNull() && !data.MaxRetryWait.IsUnknown() {
+		if data.MaxRetryWait.Value < 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("max_retry_wait"),
+				"Invalid Max Retry Wait",
+				fmt.Sprintf("max_retry_wait must not be negative, got %d.", data.MaxRetryWait.Value),
+			)
+		}
+		maxRetryWait = time.Duration(data.MaxRetryWait.Value) * time.Second
+	}
+
+	requestTimeout := time.Duration(0)
+	if !data.RequestTimeout.IsNull() && !data.RequestTimeout.IsUnknown() {
+		if data.RequestTimeout.Value < 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("request_timeout"),
+				"Invalid Request Timeout",
+				fmt.Sprintf("request_timeout must not be negative, got %d.", data.RequestTimeout.Value),
+			)
+		}
+		requestTimeout = time.Duration(data.RequestTimeout.Value) * time.Second
+	}
+
+	if domain == "" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("domain"),
+			"Missing Mastodon Domain",
+			"The provider cannot create the Mastodon client as there is a missing or empty value for the domain. "+
+				"Set the domain value in the configuration or use the "+envDomain+" environment variable.",
+		)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	transport, diags := newTransport(transportConfig{
+		CACertFile:         data.CACertFile.Value,
+		CACertPEM:          data.CACertPEM.Value,
+		ClientCertPEM:      data.ClientCertPEM.Value,
+		ClientKeyPEM:       data.ClientKeyPEM.Value,
+		InsecureSkipVerify: data.InsecureSkipVerify.Value,
+		ProxyURL:           data.ProxyURL.Value,
+		RequestTimeout:     requestTimeout,
+		MaxRetries:         maxRetries,
+		MaxRetryWait:       maxRetryWait,
+	})
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	p.accessToken = accessToken
+	p.appTokens = newTokenCache()
+	p.transport = transport
+	p.domain = domain
 	p.schema = "https"
-	if !data.UseHTTPS.IsNull() && !data.UseHTTPS.Value {
+	if !useHTTPS {
 		p.schema = "http"
 	}
+	p.serverInfo = p.detectServer(ctx)
+	tflog.Info(ctx, "detected server software", map[string]interface{}{
+		"software": p.serverInfo.Software,
+		"version":  fmt.Sprint(p.serverInfo.Version),
+	})
 	p.configured = true
 }
 
 func (p *mastodonProvider) GetResources(_ context.Context) (map[string]provider.ResourceType, diag.Diagnostics) {
 	return map[string]provider.ResourceType{
-		"mastodon_register_app": registerAppResourceType{},
+		"mastodon_account_preferences":   accountPreferencesResourceType{},
+		"mastodon_account_profile":       accountProfileResourceType{},
+		"mastodon_canonical_email_block": canonicalEmailBlockResourceType{},
+		"mastodon_domain_allow":          domainAllowResourceType{},
+		"mastodon_domain_block":          domainBlockResourceType{},
+		"mastodon_domain_blocks":         domainBlocksResourceType{},
+		"mastodon_email_domain_block":    emailDomainBlockResourceType{},
+		"mastodon_ip_block":              ipBlockResourceType{},
+		"mastodon_register_app":          registerAppResourceType{},
+		"mastodon_status":                statusResourceType{},
 	}, nil
 }
 
 func (p *mastodonProvider) GetDataSources(_ context.Context) (map[string]provider.DataSourceType, diag.Diagnostics) {
 	return map[string]provider.DataSourceType{
-		"mastodon_account":       accountDataSourceType{},
-		"mastodon_instance_self": instanceSelfDataSourceType{},
+		"mastodon_account":             accountDataSourceType{},
+		"mastodon_admin_domain_blocks": adminDomainBlocksDataSourceType{},
+		"mastodon_domain_blocklist":    domainBlocklistDataSourceType{},
+		"mastodon_instance_self":       instanceSelfDataSourceType{},
 	}, nil
 }
 
 func (p *mastodonProvider) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
 	return tfsdk.Schema{
 		Attributes: map[string]tfsdk.Attribute{
+			"access_token": {
+				MarkdownDescription: "Access token used to authenticate requests. May also be provided via the `" + envAccessToken + "` environment variable.",
+				Optional:            true,
+				Sensitive:           true,
+				Type:                types.StringType,
+			},
+			"ca_cert_file": {
+				MarkdownDescription: "Path to a PEM encoded CA bundle trusted in addition to the system certificate pool",
+				Optional:            true,
+				Type:                types.StringType,
+			},
+			"ca_cert_pem": {
+				MarkdownDescription: "PEM encoded CA bundle trusted in addition to the system certificate pool",
+				Optional:            true,
+				Type:                types.StringType,
+			},
+			"client_cert_pem": {
+				MarkdownDescription: "PEM encoded client certificate used for mutual TLS. Requires `client_key_pem`.",
+				Optional:            true,
+				Type:                types.StringType,
+			},
+			"client_key_pem": {
+				MarkdownDescription: "PEM encoded private key for `client_cert_pem`",
+				Optional:            true,
+				Sensitive:           true,
+				Type:                types.StringType,
+			},
 			"domain": {
-				MarkdownDescription: "Domain",
-				Required:            true,
+				MarkdownDescription: "Domain. May also be provided via the `" + envDomain + "` environment variable.",
+				Optional:            true,
 				Type:                types.StringType,
 			},
+			"insecure_skip_verify": {
+				MarkdownDescription: "Skip verification of the instance's TLS certificate. Only use this for testing.",
+				Optional:            true,
+				Type:                types.BoolType,
+			},
+			"max_retries": {
+				MarkdownDescription: fmt.Sprintf("Maximum number of times a rate limited or failed request is retried. Defaults to `%d`.", defaultMaxRetries),
+				Optional:            true,
+				Type:                types.Int64Type,
+			},
+			"max_retry_wait": {
+				MarkdownDescription: fmt.Sprintf("Maximum number of seconds to wait before retrying a request or for the rate limit to reset. Defaults to `%d`.", int(defaultMaxRetryWait.Seconds())),
+				Optional:            true,
+				Type:                types.Int64Type,
+			},
+			"proxy_url": {
+				MarkdownDescription: "URL of the HTTP(S) proxy to connect through. Defaults to the proxy set in the `HTTPS_PROXY` and `HTTP_PROXY` environment variables.",
+				Optional:            true,
+				Type:                types.StringType,
+			},
+			"request_timeout": {
+				MarkdownDescription: "Number of seconds a single request may take before it's cancelled. Defaults to no timeout.",
+				Optional:            true,
+				Type:                types.Int64Type,
+			},
 			"use_https": {
-				MarkdownDescription: "Should we use https to connect to the instance",
+				MarkdownDescription: "Should we use https to connect to the instance. May also be provided via the `" + envUseHTTPS + "` environment variable.",
 				Optional:            true,
 				Type:                types.BoolType,
 			},
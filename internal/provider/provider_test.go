@@ -1,8 +1,15 @@
 package provider
 
 import (
+	"fmt"
+	"github.com/feditools/terraform-provider-mastodon/internal/fakemastodon"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -15,3 +22,84 @@ func testAccPreCheck(t *testing.T) {
 	// about the appropriate environment variables being set are common to see in a pre-check
 	// function.
 }
+
+const testAccProviderConfigTmpl = `
+provider "mastodon" {
+	domain    = %[1]q
+	use_https = false
+}
+`
+
+// testAccProviderConfig returns the provider block connecting to s.
+func testAccProviderConfig(s *fakemastodon.Server) string {
+	return fmt.Sprintf(testAccProviderConfigTmpl, s.Domain())
+}
+
+const testAccProviderConfigWithTokenTmpl = `
+provider "mastodon" {
+	domain       = %[1]q
+	access_token = %[2]q
+	use_https    = false
+}
+`
+
+// testAccProviderConfigWithToken returns the provider block connecting to s with accessToken.
+func testAccProviderConfigWithToken(s *fakemastodon.Server, accessToken string) string {
+	return fmt.Sprintf(testAccProviderConfigWithTokenTmpl, s.Domain(), accessToken)
+}
+
+// testAccCheckResourceID checks that the resource's id matches *id, recording it if *id is empty.
+func testAccCheckResourceID(name string, id *string) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		rs, ok := state.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", name)
+		}
+
+		if *id == "" {
+			*id = rs.Primary.ID
+		}
+		if rs.Primary.ID != *id {
+			return fmt.Errorf("expected resource to be updated in place, id changed from %s to %s", *id, rs.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
+func TestAccProviderEnvironment(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer env-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprintln(w, `{"error":"The access token is invalid"}`)
+			return
+		}
+		fmt.Fprintln(w, `{"email":"user@example.com","thumbnail":"https://example.com/image.jpg","title":"Example Title","uri":"example.com","version":"1.2.4"}`)
+		return
+	}))
+	defer ts.Close()
+
+	t.Setenv(envDomain, strings.TrimPrefix(ts.URL, "http://"))
+	t.Setenv(envAccessToken, "env-token")
+	t.Setenv(envUseHTTPS, "false")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderEnvironmentConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mastodon_instance_self.test", "id", "example.com"),
+				),
+			},
+		},
+	})
+}
+
+const testAccProviderEnvironmentConfig = `
+provider "mastodon" {}
+
+data "mastodon_instance_self" "test" {
+}
+`
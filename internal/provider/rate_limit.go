@@ -0,0 +1,189 @@
+package provider
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMaxRetries   = 5
+	defaultMaxRetryWait = 5 * time.Minute
+	defaultMinBackoff   = time.Second
+)
+
+// rateLimitTransport keeps track of the rate limit headers sent by the server, waiting for the
+// limit to reset before it's exceeded, and retries rate limited and failed requests with backoff.
+type rateLimitTransport struct {
+	base       http.RoundTripper
+	maxRetries int
+	maxWait    time.Duration
+	minBackoff time.Duration
+
+	lock      sync.Mutex
+	remaining int
+	reset     time.Time
+}
+
+func newRateLimitTransport(base http.RoundTripper, maxRetries int, maxWait time.Duration) *rateLimitTransport {
+	return &rateLimitTransport{
+		base:       base,
+		maxRetries: maxRetries,
+		maxWait:    maxWait,
+		minBackoff: defaultMinBackoff,
+		remaining:  -1,
+	}
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if err := sleepContext(req.Context(), t.limitWait()); err != nil {
+			return nil, err
+		}
+
+		attemptReq := req
+		if attempt > 0 {
+			attemptReq = req.Clone(req.Context())
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				attemptReq.Body = body
+			}
+		}
+
+		resp, err := t.base.RoundTrip(attemptReq)
+		if err != nil {
+			return nil, err
+		}
+		t.update(resp.Header)
+
+		if attempt >= t.maxRetries || !shouldRetry(req, resp) {
+			return resp, nil
+		}
+
+		wait := t.backoff(attempt, resp)
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBodySize))
+		_ = resp.Body.Close()
+
+		if err := sleepContext(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// limitWait returns how long to wait before sending a request so the rate limit isn't exceeded.
+func (t *rateLimitTransport) limitWait() time.Duration {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.remaining != 0 {
+		return 0
+	}
+
+	wait := time.Until(t.reset)
+	if wait <= 0 {
+		// limit has reset, allow requests until the server tells us otherwise
+		t.remaining = -1
+
+		return 0
+	}
+
+	return t.capWait(wait)
+}
+
+// update records the rate limit state from the response headers.
+func (t *rateLimitTransport) update(header http.Header) {
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	reset, err := time.Parse(time.RFC3339Nano, header.Get("X-RateLimit-Reset"))
+	if err != nil {
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.remaining = remaining
+	t.reset = reset
+}
+
+// backoff returns how long to wait before retrying after resp. Rate limited responses wait for
+// the server's reset time, everything else uses jittered exponential backoff.
+func (t *rateLimitTransport) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp.StatusCode == http.StatusTooManyRequests {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			return t.capWait(time.Duration(seconds) * time.Second)
+		}
+		if reset, err := time.Parse(time.RFC3339Nano, resp.Header.Get("X-RateLimit-Reset")); err == nil {
+			if wait := time.Until(reset); wait > 0 {
+				return t.capWait(wait)
+			}
+		}
+	}
+
+	wait := t.minBackoff << attempt
+	if wait <= 0 || wait > t.maxWait {
+		wait = t.maxWait
+	}
+
+	// full jitter over the upper half of the window
+	half := wait / 2
+	if half > 0 {
+		wait = half + time.Duration(rand.Int63n(int64(half)))
+	}
+
+	return wait
+}
+
+func (t *rateLimitTransport) capWait(wait time.Duration) time.Duration {
+	if wait > t.maxWait {
+		return t.maxWait
+	}
+
+	return wait
+}
+
+// shouldRetry returns true if req can be safely sent again after receiving resp.
+func shouldRetry(req *http.Request, resp *http.Response) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		// the request wasn't processed
+		return true
+	case resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented:
+		// only retry requests that are safe to send more than once
+		switch req.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+			return true
+		}
+	}
+
+	return false
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,98 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testRateLimitTransport(maxRetries int) *rateLimitTransport {
+	t := newRateLimitTransport(http.DefaultTransport, maxRetries, time.Second)
+	t.minBackoff = time.Millisecond
+
+	return t
+}
+
+func TestRateLimitTransport_Retry(t *testing.T) {
+	tables := []struct {
+		method     string
+		status     int
+		maxRetries int
+		wantStatus int
+		wantCalls  int32
+	}{
+		{http.MethodGet, http.StatusTooManyRequests, 3, http.StatusOK, 3},
+		{http.MethodPost, http.StatusTooManyRequests, 3, http.StatusOK, 3},
+		{http.MethodGet, http.StatusBadGateway, 3, http.StatusOK, 3},
+		{http.MethodPost, http.StatusBadGateway, 3, http.StatusBadGateway, 1},
+		{http.MethodGet, http.StatusTooManyRequests, 1, http.StatusTooManyRequests, 2},
+		{http.MethodGet, http.StatusNotFound, 3, http.StatusNotFound, 1},
+	}
+
+	for i, table := range tables {
+		i := i
+		table := table
+
+		t.Run(fmt.Sprintf("[%d] %s %d", i, table.method, table.status), func(t *testing.T) {
+			var calls int32
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodPost {
+					if err := r.ParseForm(); err != nil || r.PostForm.Get("a") != "b" {
+						t.Errorf("[%d] request body not replayed", i)
+					}
+				}
+				if atomic.AddInt32(&calls, 1) < 3 {
+					w.WriteHeader(table.status)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer ts.Close()
+
+			req, _ := http.NewRequest(table.method, ts.URL, strings.NewReader("a=b"))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+			resp, err := testRateLimitTransport(table.maxRetries).RoundTrip(req)
+			if err != nil {
+				t.Fatalf("[%d] unexpected error: %s", i, err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != table.wantStatus {
+				t.Errorf("[%d] unexpected status, got: %d, want: %d", i, resp.StatusCode, table.wantStatus)
+			}
+			if calls != table.wantCalls {
+				t.Errorf("[%d] unexpected call count, got: %d, want: %d", i, calls, table.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRateLimitTransport_WaitForReset(t *testing.T) {
+	reset := time.Now().Add(200 * time.Millisecond)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "300")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", reset.UTC().Format(time.RFC3339Nano))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	transport := testRateLimitTransport(0)
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequest(http.MethodGet, ts.URL, nil)
+		resp, err := transport.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		resp.Body.Close()
+	}
+
+	if time.Now().Before(reset) {
+		t.Errorf("second request was sent before the rate limit reset")
+	}
+}
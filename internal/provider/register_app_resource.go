@@ -2,43 +2,81 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/mattn/go-mastodon"
+	"net/http"
+	"net/url"
+	"sort"
 	"strings"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces
 var _ provider.ResourceType = registerAppResourceType{}
 var _ resource.Resource = registerAppResource{}
+var _ resource.ResourceWithModifyPlan = registerAppResource{}
+var _ resource.ResourceWithImportState = registerAppResource{}
+
+// defaultAppScopes are the scopes apps are registered with unless configured otherwise.
+var defaultAppScopes = []string{"read", "write", "follow", "admin:read", "admin:write"}
+
+// defaultAppRedirectURI is the redirect URI apps are registered with unless configured otherwise, which
+// shows the authorization code instead of redirecting.
+const defaultAppRedirectURI = "urn:ietf:wg:oauth:2.0:oob"
+
+// registerAppConfigTypes are the attribute types of app_config.
+var registerAppConfigTypes = map[string]attr.Type{
+	"client_id":     types.StringType,
+	"client_secret": types.StringType,
+	"redirect_uri":  types.StringType,
+}
 
 type registerAppResourceType struct{}
 
 func (t registerAppResourceType) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diagnostics) {
 	return tfsdk.Schema{
-		MarkdownDescription: "Register Application",
+		MarkdownDescription: "Register Application. Applications can't be changed once registered, so changing any input registers a new application with new credentials. Destroying the resource only revokes the application's tokens issued during the same Terraform run, because the server can't list an application's tokens. Tokens issued during earlier runs stay valid until the application is deleted, so set `delete_app_on_destroy` on servers that support it to invalidate all of them.",
 
 		Attributes: map[string]tfsdk.Attribute{
 			// inputs
 			"client_name": {
 				MarkdownDescription: "Name to register application with",
 				Optional:            true,
+				Computed:            true,
 				Type:                types.StringType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+					resource.RequiresReplace(),
+				},
+			},
+			"delete_app_on_destroy": {
+				MarkdownDescription: "Delete the application when the resource is destroyed, on servers that support it. Defaults to `false`.",
+				Optional:            true,
+				Type:                types.BoolType,
+			},
+			"ignore_revoke_errors": {
+				MarkdownDescription: "Report failures to revoke the application's tokens on destroy as warnings instead of errors. Defaults to `false`.",
+				Optional:            true,
+				Type:                types.BoolType,
 			},
 			"redirect_uris": {
-				MarkdownDescription: "Redirect URI to register application with",
+				MarkdownDescription: "Redirect URI to register application with. Defaults to `" + defaultAppRedirectURI + "`.",
 				Optional:            true,
+				Computed:            true,
 				Type:                types.StringType,
 			},
 			"scopes": {
-				MarkdownDescription: "OAuth scopes",
+				MarkdownDescription: "OAuth scopes. Defaults to `" + strings.Join(defaultAppScopes, " ") + "`.",
 				Optional:            true,
+				Computed:            true,
 				Type: types.ListType{
 					ElemType: types.StringType,
 				},
@@ -46,7 +84,12 @@ func (t registerAppResourceType) GetSchema(ctx context.Context) (tfsdk.Schema, d
 			"website": {
 				MarkdownDescription: "Website for registered application",
 				Optional:            true,
+				Computed:            true,
 				Type:                types.StringType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+					resource.RequiresReplace(),
+				},
 			},
 
 			// outputs
@@ -54,17 +97,19 @@ func (t registerAppResourceType) GetSchema(ctx context.Context) (tfsdk.Schema, d
 				MarkdownDescription: "identifier",
 				Type:                types.StringType,
 				Computed:            true,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
 			},
 			"app_config": {
 				MarkdownDescription: "Application auth config",
 				Type: types.ObjectType{
-					AttrTypes: map[string]attr.Type{
-						"client_id":     types.StringType,
-						"client_secret": types.StringType,
-						"redirect_uri":  types.StringType,
-					},
+					AttrTypes: registerAppConfigTypes,
 				},
 				Computed: true,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
 			},
 		},
 	}, nil
@@ -79,87 +124,203 @@ func (t registerAppResourceType) NewResource(_ context.Context, in provider.Prov
 }
 
 type registerAppResourceData struct {
-	ClientName   types.String `tfsdk:"client_name"`
-	RedirectURIs types.String `tfsdk:"redirect_uris"`
-	Scopes       types.List   `tfsdk:"scopes"`
-	Website      types.String `tfsdk:"website"`
+	ClientName         types.String `tfsdk:"client_name"`
+	DeleteAppOnDestroy types.Bool   `tfsdk:"delete_app_on_destroy"`
+	IgnoreRevokeErrors types.Bool   `tfsdk:"ignore_revoke_errors"`
+	RedirectURIs       types.String `tfsdk:"redirect_uris"`
+	Scopes             types.List   `tfsdk:"scopes"`
+	Website            types.String `tfsdk:"website"`
 
 	ID        types.String `tfsdk:"id"`
 	AppConfig types.Object `tfsdk:"app_config"`
 }
 
+// scopes returns the space separated scopes the app is registered with.
+func (d registerAppResourceData) scopes() string {
+	if d.Scopes.IsNull() || d.Scopes.IsUnknown() {
+		return strings.Join(defaultAppScopes, " ")
+	}
+
+	var sb strings.Builder
+	lastScope := len(d.Scopes.Elems) - 1
+	for i, scope := range d.Scopes.Elems {
+		scopeString := scope.(types.String)
+		sb.WriteString(scopeString.Value)
+		if i != lastScope {
+			sb.WriteString(" ")
+		}
+	}
+
+	return sb.String()
+}
+
+// scopeList converts space separated scopes to a list.
+func scopeList(scopes string) types.List {
+	list := types.List{
+		ElemType: types.StringType,
+		Elems:    []attr.Value{},
+	}
+	for _, scope := range strings.Fields(scopes) {
+		list.Elems = append(list.Elems, types.String{Value: scope})
+	}
+
+	return list
+}
+
+// sameScopes returns true if a and b are known and hold the same scopes in any order.
+func sameScopes(a, b registerAppResourceData) bool {
+	if a.Scopes.IsUnknown() || b.Scopes.IsUnknown() {
+		return false
+	}
+
+	aScopes := strings.Fields(a.scopes())
+	bScopes := strings.Fields(b.scopes())
+	sort.Strings(aScopes)
+	sort.Strings(bScopes)
+
+	return strings.Join(aScopes, " ") == strings.Join(bScopes, " ")
+}
+
+// appConfig returns the registration inputs, filling in defaults for unset values.
+func (d registerAppResourceData) appConfig() *mastodon.AppConfig {
+	clientName := "terraform-provider-mastodon"
+	if !d.ClientName.IsNull() && !d.ClientName.IsUnknown() {
+		clientName = d.ClientName.Value
+	}
+
+	redirectURIs := defaultAppRedirectURI
+	if !d.RedirectURIs.IsNull() && !d.RedirectURIs.IsUnknown() {
+		redirectURIs = d.RedirectURIs.Value
+	}
+
+	website := "https://github.com/feditools/terraform-provider-mastodon"
+	if !d.Website.IsNull() && !d.Website.IsUnknown() {
+		website = d.Website.Value
+	}
+
+	return &mastodon.AppConfig{
+		ClientName:   clientName,
+		Scopes:       d.scopes(),
+		Website:      website,
+		RedirectURIs: redirectURIs,
+	}
+}
+
+func newRegisterAppConfig(clientID, clientSecret string, redirectURI types.String) types.Object {
+	return types.Object{
+		AttrTypes: registerAppConfigTypes,
+		Attrs: map[string]attr.Value{
+			"client_id":     types.String{Value: clientID},
+			"client_secret": types.String{Value: clientSecret},
+			"redirect_uri":  redirectURI,
+		},
+	}
+}
+
+// appVerification is an application as returned by verify_credentials. Mastodon reports the id, redirect
+// uri and scopes since 4.3.
+type appVerification struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Website     string   `json:"website"`
+	RedirectURI *string  `json:"redirect_uri"`
+	Scopes      []string `json:"scopes"`
+}
+
 type registerAppResource struct {
 	provider mastodonProvider
 }
 
-func (r registerAppResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	var data registerAppResourceData
+func (r registerAppResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// nothing to check when destroying
+	if req.Plan.Raw.IsNull() {
+		return
+	}
 
-	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(r.provider.requireCapability(capVerifyAppCredentials, "mastodon_register_app")...)
+
+	var config, plan registerAppResourceData
+
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+
+	diags = req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// collect inputs
-	clientName := "terraform-provider-mastodon"
-	if !data.ClientName.IsNull() {
-		clientName = data.ClientName.Value
+	// unset inputs are registered with their defaults
+	if config.Scopes.IsNull() {
+		plan.Scopes = scopeList(plan.scopes())
 	}
+	if config.RedirectURIs.IsNull() {
+		plan.RedirectURIs = types.String{Value: defaultAppRedirectURI}
+	}
+
+	diags = resp.Plan.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
 
-	redirectURIs := "urn:ietf:wg:oauth:2.0:oob"
-	if !data.RedirectURIs.IsNull() {
-		redirectURIs = data.RedirectURIs.Value
+	if req.State.Raw.IsNull() {
+		return
 	}
 
-	scopes := "read write follow admin:read admin:write"
-	if !data.Scopes.IsNull() {
-		var sb strings.Builder
-		lastScope := len(data.Scopes.Elems) - 1
-		for i, scope := range data.Scopes.Elems {
-			scopeString := scope.(types.String)
-			sb.WriteString(scopeString.Value)
-			if i != lastScope {
-				sb.WriteString(" ")
-			}
-		}
+	var state registerAppResourceData
+
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
 
-		scopes = sb.String()
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	website := "https://github.com/feditools/terraform-provider-mastodon"
-	if !data.Website.IsNull() {
-		website = data.Website.Value
+	// the registration of imported apps is unknown if the server doesn't report it, so whatever is
+	// configured is taken as is
+	if !state.Scopes.IsNull() && !sameScopes(plan, state) {
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("scopes"))
+	}
+	if !state.RedirectURIs.IsNull() && !plan.RedirectURIs.Equal(state.RedirectURIs) {
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("redirect_uris"))
+	}
+}
+
+func (r registerAppResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data registerAppResourceData
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
 	// do registration
-	app, err := mastodon.RegisterApp(ctx, &mastodon.AppConfig{
-		Server:       r.provider.server(),
-		ClientName:   clientName,
-		Scopes:       scopes,
-		Website:      website,
-		RedirectURIs: redirectURIs,
-	})
+	appConfig := data.appConfig()
+	params := url.Values{
+		"client_name":   {appConfig.ClientName},
+		"redirect_uris": {appConfig.RedirectURIs},
+		"scopes":        {appConfig.Scopes},
+		"website":       {appConfig.Website},
+	}
+
+	var app mastodon.Application
+	_, err := doAPI(ctx, r.provider.newUnauthenticatedClient(), http.MethodPost, "/api/v1/apps", params, &app)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to register application, got error: %s", err.Error()))
+		addAPIError(&resp.Diagnostics, "register application", err)
 
 		return
 	}
 
+	data.ClientName = types.String{Value: appConfig.ClientName}
+	data.RedirectURIs = types.String{Value: appConfig.RedirectURIs}
+	data.Scopes = scopeList(appConfig.Scopes)
+	data.Website = types.String{Value: appConfig.Website}
 	data.ID = types.String{Value: string(app.ID)}
-	data.AppConfig = types.Object{
-		AttrTypes: map[string]attr.Type{
-			"client_id":     types.StringType,
-			"client_secret": types.StringType,
-			"redirect_uri":  types.StringType,
-		},
-		Attrs: map[string]attr.Value{
-			"client_id":     types.String{Value: app.ClientID},
-			"client_secret": types.String{Value: app.ClientSecret},
-			"redirect_uri":  types.String{Value: app.RedirectURI},
-		},
+	if app.ID == "" {
+		data.ID = types.String{Value: app.ClientID}
 	}
+	data.AppConfig = newRegisterAppConfig(app.ClientID, app.ClientSecret, types.String{Value: app.RedirectURI})
 
 	tflog.Trace(ctx, "created a resource")
 
@@ -180,27 +341,34 @@ func (r registerAppResource) Read(ctx context.Context, req resource.ReadRequest,
 	clientID := data.AppConfig.Attrs["client_id"].(types.String).Value
 	clientSecret := data.AppConfig.Attrs["client_secret"].(types.String).Value
 
-	client, err := r.provider.newAuthenticatedClient(ctx, clientID, clientSecret, "")
+	// apps without configured scopes, such as imported ones, are verified with the server's default scope
+	scopes := ""
+	if !data.Scopes.IsNull() {
+		scopes = data.scopes()
+	}
+
+	client, err := r.provider.newAuthenticatedClient(ctx, clientID, clientSecret, scopes, "")
 	if err != nil {
-		if strings.HasPrefix("bad authorization: 401 Unauthorized:", err.Error()) {
+		if errors.Is(err, errUnauthorized) || isNotFound(err) {
 			resp.State.RemoveResource(ctx)
 
 			return
 		}
 
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create new client, got error: %s", err))
+		addAPIError(&resp.Diagnostics, "authenticate application", err)
 
 		return
 	}
 
-	_, err = client.VerifyAppCredentials(ctx)
+	_, err = doAPI(ctx, client, http.MethodGet, "/api/v1/apps/verify_credentials", nil, nil)
 	if err != nil {
-		if strings.HasPrefix("bad request: 401 Unauthorized:", err.Error()) {
+		if errors.Is(err, errUnauthorized) || isNotFound(err) {
 			resp.State.RemoveResource(ctx)
 
 			return
 		}
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read example, got error: %s", err))
+
+		addAPIError(&resp.Diagnostics, "verify application credentials", err)
 
 		return
 	}
@@ -212,86 +380,211 @@ func (r registerAppResource) Read(ctx context.Context, req resource.ReadRequest,
 func (r registerAppResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	var data registerAppResourceData
 
-	diags := req.Config.Get(ctx, &data)
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	var state registerAppResourceData
+
+	diags = req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// collect inputs
-	clientName := "terraform-provider-mastodon"
-	if !data.ClientName.IsNull() {
-		clientName = data.ClientName.Value
+	// inputs that change the registration require replacement, so the registered app is unchanged
+	appConfig := data.appConfig()
+	data.ClientName = types.String{Value: appConfig.ClientName}
+	data.Website = types.String{Value: appConfig.Website}
+	data.ID = state.ID
+	data.AppConfig = state.AppConfig
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r registerAppResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data registerAppResourceData
+
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	redirectURIs := "urn:ietf:wg:oauth:2.0:oob"
-	if !data.RedirectURIs.IsNull() {
-		redirectURIs = data.RedirectURIs.Value
+	clientID := data.AppConfig.Attrs["client_id"].(types.String).Value
+	clientSecret := data.AppConfig.Attrs["client_secret"].(types.String).Value
+
+	// deleting the app invalidates its tokens, so there's nothing left to revoke
+	if data.DeleteAppOnDestroy.Value && r.deleteApp(ctx, data.ID.Value, clientID, clientSecret, &resp.Diagnostics) {
+		r.provider.appTokens.take(clientID)
+
+		return
 	}
 
-	scopes := "read write follow admin:read admin:write"
-	if !data.Scopes.IsNull() {
-		var sb strings.Builder
-		lastScope := len(data.Scopes.Elems) - 1
-		for i, scope := range data.Scopes.Elems {
-			scopeString := scope.(types.String)
-			sb.WriteString(scopeString.Value)
-			if i != lastScope {
-				sb.WriteString(" ")
-			}
+	reportError := func(action string, err error) {
+		if data.IgnoreRevokeErrors.Value {
+			resp.Diagnostics.AddWarning(
+				"Unable to Revoke Token",
+				"Unable to "+action+" of application "+clientID+": "+err.Error(),
+			)
+
+			return
 		}
 
-		scopes = sb.String()
+		addAPIError(&resp.Diagnostics, action, err)
 	}
 
-	website := "https://github.com/feditools/terraform-provider-mastodon"
-	if !data.Website.IsNull() {
-		website = data.Website.Value
+	// only tokens requested by this run are cached, so a destroy that didn't read the app first has its
+	// token issued here to revoke it. Tokens issued by earlier runs can't be listed, so they stay valid.
+	tokens := r.provider.appTokens.take(clientID)
+	if len(tokens) == 0 {
+		scopes := ""
+		if !data.Scopes.IsNull() {
+			scopes = data.scopes()
+		}
+
+		token, err := r.provider.requestAppToken(ctx, clientID, clientSecret, scopes)
+		switch {
+		// the app no longer exists, so neither do its tokens
+		case errors.Is(err, errUnauthorized) || isNotFound(err):
+			return
+		case err != nil:
+			reportError("request token", err)
+
+			return
+		}
+		tokens = append(tokens, token)
 	}
 
-	// do registration
-	app, err := mastodon.RegisterApp(ctx, &mastodon.AppConfig{
-		Server:       r.provider.server(),
-		ClientName:   clientName,
-		Scopes:       scopes,
-		Website:      website,
-		RedirectURIs: redirectURIs,
-	})
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to register application, got error: %s", err.Error()))
+	for _, token := range tokens {
+		if err := r.provider.revokeAppToken(ctx, clientID, clientSecret, token); err != nil {
+			reportError("revoke token", err)
+		}
+	}
+}
 
-		return
+// deleteApp deletes the application with the server's app id, returning true if it no longer exists.
+// Servers that can't delete applications result in a warning.
+func (r registerAppResource) deleteApp(ctx context.Context, id, clientID, clientSecret string, diags *diag.Diagnostics) bool {
+	// only delete where it's known to work, since a missing endpoint would look like a deleted app
+	if supported, known := r.provider.serverInfo.supports(capDeleteApp); !supported || !known {
+		diags.AddWarning(
+			"Unable to Delete Application",
+			fmt.Sprintf("%s doesn't support deleting applications, only the application's tokens issued during this run are revoked instead.", r.provider.serverInfo),
+		)
+
+		return false
 	}
 
-	data.ID = types.String{Value: string(app.ID)}
-	data.AppConfig = types.Object{
-		AttrTypes: map[string]attr.Type{
-			"client_id":     types.StringType,
-			"client_secret": types.StringType,
-			"redirect_uri":  types.StringType,
-		},
-		Attrs: map[string]attr.Value{
-			"client_id":     types.String{Value: app.ClientID},
-			"client_secret": types.String{Value: app.ClientSecret},
-			"redirect_uri":  types.String{Value: app.RedirectURI},
-		},
+	// apps imported without their id only have their client id, which the server can't delete them by
+	if id == clientID {
+		diags.AddWarning(
+			"Unable to Delete Application",
+			"The id of application "+clientID+" is unknown, import it using an ID of the form id:client_id:client_secret to delete it. Only the application's tokens issued during this run are revoked instead.",
+		)
+
+		return false
 	}
 
-	tflog.Trace(ctx, "created a resource")
+	client := r.provider.newClient()
+	if r.provider.accessToken == "" {
+		var err error
+		client, err = r.provider.newAuthenticatedClient(ctx, clientID, clientSecret, "", "")
+		if err != nil {
+			addAPIError(diags, "authenticate application", err)
 
-	diags = resp.State.Set(ctx, &data)
-	resp.Diagnostics.Append(diags...)
+			return false
+		}
+	}
+
+	_, err := doAPI(ctx, client, http.MethodDelete, "/api/v1/apps/"+url.PathEscape(id), nil, nil)
+	if err != nil {
+		if isNotFound(err) {
+			diags.AddWarning(
+				"Application Not Found",
+				"Application "+clientID+" was already deleted.",
+			)
+
+			return true
+		}
+
+		addAPIError(diags, "delete application", err)
+
+		return false
+	}
+
+	return true
 }
 
-func (r registerAppResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	var data registerAppResourceData
+func (r registerAppResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	var id, clientID, clientSecret string
+	switch parts := strings.Split(req.ID, ":"); len(parts) {
+	case 2:
+		clientID, clientSecret = parts[0], parts[1]
+	case 3:
+		id, clientID, clientSecret = parts[0], parts[1], parts[2]
+	}
+	if clientID == "" || clientSecret == "" {
+		resp.Diagnostics.AddError(
+			"Invalid Import ID",
+			"Expected an import ID of the form client_id:client_secret or id:client_id:client_secret.",
+		)
 
-	diags := req.State.Get(ctx, &data)
-	resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	client, err := r.provider.newAuthenticatedClient(ctx, clientID, clientSecret, "", "")
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "authenticate application", err)
+
+		return
+	}
+
+	var app appVerification
+	_, err = doAPI(ctx, client, http.MethodGet, "/api/v1/apps/verify_credentials", nil, &app)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "verify application credentials", err)
+
+		return
+	}
+
+	switch {
+	case app.ID != "" && id != "" && app.ID != id:
+		resp.Diagnostics.AddError(
+			"Invalid Import ID",
+			fmt.Sprintf("The client credentials belong to application %s, not %s.", app.ID, id),
+		)
 
-	if resp.Diagnostics.HasError() {
 		return
+	case app.ID != "":
+		id = app.ID
+	case id == "":
+		// servers that don't report the id, such as Mastodon before 4.3, identify the app by its client id
+		id = clientID
 	}
 
+	// servers that don't report the redirect uri and scopes leave them to the configuration
+	data := registerAppResourceData{
+		ClientName:         types.String{Value: app.Name},
+		DeleteAppOnDestroy: types.Bool{Null: true},
+		IgnoreRevokeErrors: types.Bool{Null: true},
+		RedirectURIs:       types.String{Null: true},
+		Scopes:             types.List{ElemType: types.StringType, Null: true},
+		Website:            types.String{Value: app.Website, Null: app.Website == ""},
+
+		ID:        types.String{Value: id},
+		AppConfig: newRegisterAppConfig(clientID, clientSecret, types.String{Null: true}),
+	}
+	if app.RedirectURI != nil {
+		data.RedirectURIs = types.String{Value: *app.RedirectURI}
+		data.AppConfig = newRegisterAppConfig(clientID, clientSecret, data.RedirectURIs)
+	}
+	if app.Scopes != nil {
+		data.Scopes = scopeList(strings.Join(app.Scopes, " "))
+	}
+
+	diags := resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
 }
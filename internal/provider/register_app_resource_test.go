@@ -2,64 +2,179 @@ package provider
 
 import (
 	"fmt"
-	"net/http"
-	"net/http/httptest"
-	"strings"
 	"testing"
 
+	"github.com/feditools/terraform-provider-mastodon/internal/fakemastodon"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 func TestAccRegisterAppResource(t *testing.T) {
-	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		fmt.Fprintln(w, `{"id":"563419","name":"test app","website":"https://example.com/","redirect_uri":"urn:ietf:wg:oauth:2.0:oob","client_id":"TWhM-tNSuncnqN7DBJmoyeLnk6K3iJJ71KKXxgL1hPM","client_secret":"ZEaFUFmF0umgBX1qKJDjaU99Q31lDkOU8NutzTOoliw","vapid_key":"BCk-QqERU0q-CfYZjcuB6lnyyOYfJ2AifKqfeGIm7Z-HiTU5T9eTG5GxVA0_OH5mMlI4UkkDTpaZwozy0TzdZ2M="}`)
-		return
-	}))
-	defer ts.Close()
+	s := fakemastodon.New()
+	defer s.Close()
+
+	var clientIDs []string
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { testAccPreCheck(t) },
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckRegisterAppRevoked(s, &clientIDs),
 		Steps: []resource.TestStep{
 			// Create and Read testing
 			{
-				Config: testAccRegisterAppResourceConfig(ts.URL, "one"),
+				Config: testAccRegisterAppResourceConfig(s, "one"),
 				Check: resource.ComposeAggregateTestCheckFunc(
-					resource.TestCheckResourceAttr("mastodon_register_app.test", "id", "563419"),
-					resource.TestCheckResourceAttr("mastodon_register_app.test", "app_config.client_id", "TWhM-tNSuncnqN7DBJmoyeLnk6K3iJJ71KKXxgL1hPM"),
-					resource.TestCheckResourceAttr("mastodon_register_app.test", "app_config.client_secret", "ZEaFUFmF0umgBX1qKJDjaU99Q31lDkOU8NutzTOoliw"),
+					resource.TestCheckResourceAttrSet("mastodon_register_app.test", "id"),
+					resource.TestCheckResourceAttrSet("mastodon_register_app.test", "app_config.client_id"),
+					resource.TestCheckResourceAttrSet("mastodon_register_app.test", "app_config.client_secret"),
 					resource.TestCheckResourceAttr("mastodon_register_app.test", "app_config.redirect_uri", "urn:ietf:wg:oauth:2.0:oob"),
+					testAccCheckRegisterAppName(s, "mastodon_register_app.test", "one", &clientIDs),
 				),
 			},
-			// Create and Read testing
+			// ImportState testing
+			{
+				ResourceName:      "mastodon_register_app.test",
+				ImportState:       true,
+				ImportStateIdFunc: testAccRegisterAppImportStateID("mastodon_register_app.test"),
+				ImportStateVerify: true,
+			},
+			// Replace testing
+			{
+				Config: testAccRegisterAppResourceConfig(s, "two"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("mastodon_register_app.test", "id"),
+					testAccCheckRegisterAppName(s, "mastodon_register_app.test", "two", &clientIDs),
+					testAccCheckRegisterAppReplaced(&clientIDs),
+				),
+			},
+			// Drift testing, app deleted outside of terraform
 			{
-				Config: testAccRegisterAppResourceConfig(ts.URL, "two"),
+				PreConfig: func() {
+					for _, clientID := range clientIDs {
+						s.RemoveApp(clientID)
+					}
+				},
+				Config: testAccRegisterAppResourceConfig(s, "two"),
 				Check: resource.ComposeAggregateTestCheckFunc(
-					resource.TestCheckResourceAttr("mastodon_register_app.test", "id", "563419"),
-					resource.TestCheckResourceAttr("mastodon_register_app.test", "app_config.client_id", "TWhM-tNSuncnqN7DBJmoyeLnk6K3iJJ71KKXxgL1hPM"),
-					resource.TestCheckResourceAttr("mastodon_register_app.test", "app_config.client_secret", "ZEaFUFmF0umgBX1qKJDjaU99Q31lDkOU8NutzTOoliw"),
-					resource.TestCheckResourceAttr("mastodon_register_app.test", "app_config.redirect_uri", "urn:ietf:wg:oauth:2.0:oob"),
+					testAccCheckRegisterAppName(s, "mastodon_register_app.test", "two", &clientIDs),
 				),
 			},
 		},
 	})
 }
 
-const testAccRegisterAppResourceConfigTmplPre = `
-provider "mastodon" {
-	domain = %[1]q
-	use_https = false
+func TestAccRegisterAppResource_legacyImport(t *testing.T) {
+	s := fakemastodon.New()
+	defer s.Close()
+
+	// like Mastodon before 4.3, the app's id isn't reported when verifying its credentials
+	s.LegacyAppCredentials = true
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRegisterAppResourceConfig(s, "one"),
+			},
+			// ImportState testing, the app is identified by its client id
+			{
+				ResourceName:      "mastodon_register_app.test",
+				ImportState:       true,
+				ImportStateIdFunc: testAccRegisterAppImportStateID("mastodon_register_app.test"),
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if len(states) != 1 {
+						return fmt.Errorf("expected 1 imported app, got: %d", len(states))
+					}
+
+					attributes := states[0].Attributes
+					if clientID := attributes["app_config.client_id"]; clientID == "" || states[0].ID != clientID {
+						return fmt.Errorf("expected the app to be identified by its client id %s, got: %s", clientID, states[0].ID)
+					}
+					if attributes["client_name"] != "one" {
+						return fmt.Errorf("unexpected app name, got: %s, want: one", attributes["client_name"])
+					}
+
+					return nil
+				},
+			},
+		},
+	})
+}
+
+// testAccCheckRegisterAppName checks that the resource's app is registered with clientName, appending its
+// client id to *clientIDs.
+func testAccCheckRegisterAppName(s *fakemastodon.Server, name, clientName string, clientIDs *[]string) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		rs, ok := state.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", name)
+		}
+
+		clientID := rs.Primary.Attributes["app_config.client_id"]
+		*clientIDs = append(*clientIDs, clientID)
+
+		app := s.App(clientID)
+		if app == nil {
+			return fmt.Errorf("app %s not registered on server", clientID)
+		}
+		if app.Name != clientName {
+			return fmt.Errorf("unexpected app name, got: %s, want: %s", app.Name, clientName)
+		}
+
+		return nil
+	}
+}
+
+// testAccCheckRegisterAppReplaced checks that the last two apps in *clientIDs have different client ids.
+func testAccCheckRegisterAppReplaced(clientIDs *[]string) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		n := len(*clientIDs)
+		if n < 2 {
+			return fmt.Errorf("expected at least two recorded apps, got: %d", n)
+		}
+		if (*clientIDs)[n-1] == (*clientIDs)[n-2] {
+			return fmt.Errorf("expected app to be replaced, client id unchanged: %s", (*clientIDs)[n-1])
+		}
+
+		return nil
+	}
+}
+
+// testAccCheckRegisterAppRevoked checks that destroying the last app in *clientIDs revoked its tokens.
+func testAccCheckRegisterAppRevoked(s *fakemastodon.Server, clientIDs *[]string) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		n := len(*clientIDs)
+		if n == 0 {
+			return fmt.Errorf("no apps recorded")
+		}
+
+		clientID := (*clientIDs)[n-1]
+		if s.Revoked(clientID) == 0 {
+			return fmt.Errorf("tokens of app %s weren't revoked", clientID)
+		}
+
+		return nil
+	}
+}
+
+func testAccRegisterAppImportStateID(name string) resource.ImportStateIdFunc {
+	return func(state *terraform.State) (string, error) {
+		rs, ok := state.RootModule().Resources[name]
+		if !ok {
+			return "", fmt.Errorf("resource not found: %s", name)
+		}
+
+		return rs.Primary.Attributes["app_config.client_id"] + ":" + rs.Primary.Attributes["app_config.client_secret"], nil
+	}
 }
 
+const testAccRegisterAppResourceConfigTmpl = `
 resource "mastodon_register_app" "test" {
-    client_name = %[2]q
+    client_name = %[1]q
 }
 `
 
-func testAccRegisterAppResourceConfig(tsURL string, clientName string) string {
-	return fmt.Sprintf(
-		testAccRegisterAppResourceConfigTmplPre,
-		strings.TrimPrefix(tsURL, "http://"),
-		clientName,
-	)
+func testAccRegisterAppResourceConfig(s *fakemastodon.Server, clientName string) string {
+	return testAccProviderConfig(s) + fmt.Sprintf(testAccRegisterAppResourceConfigTmpl, clientName)
 }
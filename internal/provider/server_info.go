@@ -0,0 +1,279 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/mattn/go-mastodon"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+const (
+	softwareAkkoma     = "akkoma"
+	softwareGoToSocial = "gotosocial"
+	softwareMastodon   = "mastodon"
+	softwarePleroma    = "pleroma"
+)
+
+// capability is an API feature that isn't available on every server.
+type capability string
+
+const (
+	capAdminCanonicalEmailBlocks capability = "admin canonical email blocks"
+	capAdminDomainAllows         capability = "admin domain allows"
+	capAdminDomainBlocks         capability = "admin domain blocks"
+	capAdminEmailDomainBlocks    capability = "admin email domain blocks"
+	capAdminIPBlocks             capability = "admin ip blocks"
+	capDeleteApp                 capability = "delete app"
+	capVerifyAppCredentials      capability = "verify app credentials"
+)
+
+// unsupported marks server software that's known to lack a capability.
+const unsupported = ""
+
+// capabilities lists the minimum version of each server software that supports a capability, or
+// unsupported if it doesn't. Whether software missing from a capability's list supports it is unknown.
+// Akkoma and Pleroma have their own admin API instead of Mastodon's.
+var capabilities = map[capability]map[string]string{
+	capAdminCanonicalEmailBlocks: {
+		softwareAkkoma:     unsupported,
+		softwareGoToSocial: unsupported,
+		softwareMastodon:   "4.0.0",
+		softwarePleroma:    unsupported,
+	},
+	capAdminDomainAllows: {
+		softwareAkkoma:     unsupported,
+		softwareGoToSocial: "0.12.0",
+		softwareMastodon:   "4.0.0",
+		softwarePleroma:    unsupported,
+	},
+	capAdminDomainBlocks: {
+		softwareAkkoma:     unsupported,
+		softwareGoToSocial: "0.1.0",
+		softwareMastodon:   "4.0.0",
+		softwarePleroma:    unsupported,
+	},
+	capAdminEmailDomainBlocks: {
+		softwareAkkoma:     unsupported,
+		softwareGoToSocial: unsupported,
+		softwareMastodon:   "4.0.0",
+		softwarePleroma:    unsupported,
+	},
+	capAdminIPBlocks: {
+		softwareAkkoma:     unsupported,
+		softwareGoToSocial: unsupported,
+		softwareMastodon:   "4.0.0",
+		softwarePleroma:    unsupported,
+	},
+	capDeleteApp: {
+		softwareAkkoma:     unsupported,
+		softwareGoToSocial: "0.18.0",
+		softwareMastodon:   unsupported,
+		softwarePleroma:    unsupported,
+	},
+	capVerifyAppCredentials: {
+		softwareAkkoma:     "1.0.0",
+		softwareGoToSocial: "0.1.0",
+		softwareMastodon:   "2.0.0",
+		softwarePleroma:    "1.0.0",
+	},
+}
+
+var (
+	// nodeinfoSchemaPrefix is the prefix of the rel of 2.x nodeinfo links.
+	nodeinfoSchemaPrefix = "http://nodeinfo.diaspora.software/ns/schema/2."
+
+	// versionCompatibleRegex matches versions of servers advertising Mastodon compatibility,
+	// for example "2.7.2 (compatible; Akkoma 3.5.0)".
+	versionCompatibleRegex = regexp.MustCompile(`\(compatible; ([A-Za-z]+) ([^)\s]+)\)`)
+
+	// versionRegex matches the leading version number of a version string.
+	versionRegex = regexp.MustCompile(`^v?\d+(\.\d+)*(-[0-9A-Za-z.]+)?`)
+)
+
+// serverInfo identifies the software the instance is running.
+type serverInfo struct {
+	Software string
+	Version  *version.Version
+}
+
+func (s *serverInfo) String() string {
+	if s.Version == nil {
+		return s.Software
+	}
+
+	return s.Software + " " + s.Version.String()
+}
+
+// known returns true if both the software and its version were detected.
+func (s *serverInfo) known() bool {
+	return s != nil && s.Software != "" && s.Version != nil
+}
+
+// supports returns true if the server supports c. Servers that couldn't be identified, or whose support
+// for c is unknown, are assumed to support it, leaving the server to reject requests it doesn't understand.
+// known is false in that case.
+func (s *serverInfo) supports(c capability) (supported, known bool) {
+	if !s.known() {
+		return true, false
+	}
+
+	minVersion, ok := capabilities[c][s.Software]
+	if !ok {
+		return true, false
+	}
+	if minVersion == unsupported {
+		return false, true
+	}
+
+	return s.Version.Core().GreaterThanOrEqual(version.Must(version.NewVersion(minVersion))), true
+}
+
+// requireCapability returns an error diagnostic if the server doesn't support c, and a warning if it was
+// identified but its support for c is unknown.
+func (p *mastodonProvider) requireCapability(c capability, name string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	supported, known := p.serverInfo.supports(c)
+	if supported {
+		if !known && p.serverInfo.known() {
+			diags.AddWarning(
+				"Unknown Server Feature Support",
+				fmt.Sprintf("%s requires the %s API, which may not be supported by %s.", name, c, p.serverInfo),
+			)
+		}
+
+		return diags
+	}
+
+	detail := fmt.Sprintf("%s requires the %s API, which %s doesn't support.", name, c, p.serverInfo)
+	if minVersion := capabilities[c][p.serverInfo.Software]; minVersion != unsupported {
+		detail = fmt.Sprintf("%s requires the %s API, which is available in %s %s or later. The server is running %s.", name, c, p.serverInfo.Software, minVersion, p.serverInfo)
+	}
+	diags.AddError("Unsupported Server Feature", detail)
+
+	return diags
+}
+
+// detectServer identifies the server software using nodeinfo, falling back to the version reported
+// by the instance endpoint.
+func (p *mastodonProvider) detectServer(ctx context.Context) *serverInfo {
+	info, err := p.detectServerNodeinfo(ctx)
+	if err == nil && info.known() {
+		return info
+	}
+	if err != nil {
+		tflog.Debug(ctx, "unable to read nodeinfo", map[string]interface{}{"error": err.Error()})
+	}
+
+	var instance mastodon.Instance
+	_, err = doAPI(ctx, p.newUnauthenticatedClient(), http.MethodGet, "/api/v1/instance", nil, &instance)
+	if err != nil {
+		tflog.Warn(ctx, "unable to detect server software, capability checks are disabled", map[string]interface{}{"error": err.Error()})
+
+		return &serverInfo{}
+	}
+
+	return parseInstanceVersion(instance.Version)
+}
+
+func (p *mastodonProvider) detectServerNodeinfo(ctx context.Context) (*serverInfo, error) {
+	var wellKnown struct {
+		Links []struct {
+			Rel  string `json:"rel"`
+			Href string `json:"href"`
+		} `json:"links"`
+	}
+	if err := p.getJSON(ctx, p.server()+"/.well-known/nodeinfo", &wellKnown); err != nil {
+		return nil, err
+	}
+
+	// pick the newest 2.x schema offered
+	var href, rel string
+	for _, link := range wellKnown.Links {
+		if strings.HasPrefix(link.Rel, nodeinfoSchemaPrefix) && link.Rel > rel {
+			href, rel = link.Href, link.Rel
+		}
+	}
+	if href == "" {
+		return nil, fmt.Errorf("no nodeinfo 2.x link found")
+	}
+
+	var nodeinfo struct {
+		Software struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"software"`
+	}
+	if err := p.getJSON(ctx, href, &nodeinfo); err != nil {
+		return nil, err
+	}
+
+	return &serverInfo{
+		Software: strings.ToLower(nodeinfo.Software.Name),
+		Version:  parseVersion(nodeinfo.Software.Version),
+	}, nil
+}
+
+// parseInstanceVersion identifies the server from the version reported by the instance endpoint.
+func parseInstanceVersion(v string) *serverInfo {
+	if match := versionCompatibleRegex.FindStringSubmatch(v); match != nil {
+		return &serverInfo{
+			Software: strings.ToLower(match[1]),
+			Version:  parseVersion(match[2]),
+		}
+	}
+	if strings.Contains(v, "git-") {
+		// gotosocial appends the commit to its version
+		return &serverInfo{
+			Software: softwareGoToSocial,
+			Version:  parseVersion(v),
+		}
+	}
+
+	return &serverInfo{
+		Software: softwareMastodon,
+		Version:  parseVersion(v),
+	}
+}
+
+// parseVersion parses the leading version number of v, returning nil if there isn't one.
+func parseVersion(v string) *version.Version {
+	match := versionRegex.FindString(strings.TrimSpace(v))
+	if match == "" {
+		return nil
+	}
+
+	parsed, err := version.NewVersion(match)
+	if err != nil {
+		return nil
+	}
+
+	return parsed
+}
+
+// getJSON decodes the JSON document at u into res.
+func (p *mastodonProvider) getJSON(ctx context.Context, u string, res interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	httpClient := p.httpClient()
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	if err := checkResponse(resp); err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return json.NewDecoder(resp.Body).Decode(res)
+}
@@ -0,0 +1,118 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseInstanceVersion(t *testing.T) {
+	tables := []struct {
+		version      string
+		wantSoftware string
+		wantVersion  string
+	}{
+		{"4.1.2", softwareMastodon, "4.1.2"},
+		{"4.2.0-beta1", softwareMastodon, "4.2.0-beta1"},
+		{"4.0.2+glitch", softwareMastodon, "4.0.2"},
+		{"2.7.2 (compatible; Akkoma 3.5.0)", softwareAkkoma, "3.5.0"},
+		{"2.7.2 (compatible; Pleroma 2.5.0)", softwarePleroma, "2.5.0"},
+		{"0.9.0 git-282be6f", softwareGoToSocial, "0.9.0"},
+	}
+
+	for i, table := range tables {
+		info := parseInstanceVersion(table.version)
+		if info.Software != table.wantSoftware {
+			t.Errorf("[%d] unexpected software, got: %s, want: %s", i, info.Software, table.wantSoftware)
+		}
+		if info.Version == nil || info.Version.String() != table.wantVersion {
+			t.Errorf("[%d] unexpected version, got: %v, want: %s", i, info.Version, table.wantVersion)
+		}
+	}
+}
+
+func TestServerInfo_Supports(t *testing.T) {
+	capabilities[capability("test")] = map[string]string{softwareMastodon: "4.0.0", softwareAkkoma: unsupported}
+	defer delete(capabilities, capability("test"))
+
+	tables := []struct {
+		info      *serverInfo
+		want      bool
+		wantKnown bool
+	}{
+		{&serverInfo{Software: softwareMastodon, Version: parseVersion("4.0.0")}, true, true},
+		{&serverInfo{Software: softwareMastodon, Version: parseVersion("4.0.0-rc1")}, true, true},
+		{&serverInfo{Software: softwareMastodon, Version: parseVersion("3.5.5")}, false, true},
+		{&serverInfo{Software: softwareAkkoma, Version: parseVersion("3.5.0")}, false, true},
+		{&serverInfo{Software: softwarePleroma, Version: parseVersion("2.5.0")}, true, false},
+		{&serverInfo{Software: "misskey"}, true, false},
+		{&serverInfo{}, true, false},
+		{nil, true, false},
+	}
+
+	for i, table := range tables {
+		got, gotKnown := table.info.supports(capability("test"))
+		if got != table.want || gotKnown != table.wantKnown {
+			t.Errorf("[%d] unexpected result for %v, got: %t, %t, want: %t, %t", i, table.info, got, gotKnown, table.want, table.wantKnown)
+		}
+	}
+}
+
+func TestRequireCapability(t *testing.T) {
+	tables := []struct {
+		info        *serverInfo
+		c           capability
+		wantError   bool
+		wantWarning bool
+	}{
+		{&serverInfo{Software: softwareMastodon, Version: parseVersion("4.0.0")}, capAdminDomainBlocks, false, false},
+		{&serverInfo{Software: softwareMastodon, Version: parseVersion("3.5.5")}, capAdminDomainBlocks, true, false},
+		{&serverInfo{Software: softwareGoToSocial, Version: parseVersion("0.12.0")}, capAdminDomainAllows, false, false},
+		{&serverInfo{Software: softwareGoToSocial, Version: parseVersion("0.12.0")}, capAdminIPBlocks, true, false},
+		{&serverInfo{Software: softwareAkkoma, Version: parseVersion("3.5.0")}, capAdminDomainBlocks, true, false},
+		{&serverInfo{Software: "misskey", Version: parseVersion("13.0.0")}, capAdminDomainBlocks, false, true},
+		{&serverInfo{}, capAdminDomainBlocks, false, false},
+	}
+
+	for i, table := range tables {
+		p := &mastodonProvider{serverInfo: table.info}
+
+		diags := p.requireCapability(table.c, "test")
+		if diags.HasError() != table.wantError {
+			t.Errorf("[%d] unexpected error for %v, got: %v", i, table.info, diags)
+		}
+		if (diags.WarningsCount() > 0) != table.wantWarning {
+			t.Errorf("[%d] unexpected warning for %v, got: %v", i, table.info, diags)
+		}
+	}
+}
+
+func TestDetectServer(t *testing.T) {
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/nodeinfo":
+			fmt.Fprintf(w, `{"links":[{"rel":"http://nodeinfo.diaspora.software/ns/schema/2.0","href":"%[1]s/nodeinfo/2.0"},{"rel":"http://nodeinfo.diaspora.software/ns/schema/2.1","href":"%[1]s/nodeinfo/2.1"}]}`, ts.URL)
+		case "/nodeinfo/2.1":
+			fmt.Fprint(w, `{"version":"2.1","software":{"name":"GoToSocial","version":"0.9.0 git-282be6f"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	transport, _ := newTransport(transportConfig{})
+	p := &mastodonProvider{
+		domain:    strings.TrimPrefix(ts.URL, "http://"),
+		schema:    "http",
+		transport: transport,
+	}
+
+	info := p.detectServer(context.Background())
+	if info.String() != "gotosocial 0.9.0" {
+		t.Errorf("unexpected server, got: %s, want: %s", info, "gotosocial 0.9.0")
+	}
+}
@@ -0,0 +1,476 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/mattn/go-mastodon"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ provider.ResourceType = statusResourceType{}
+var _ resource.Resource = statusResource{}
+var _ resource.ResourceWithModifyPlan = statusResource{}
+var _ resource.ResourceWithImportState = statusResource{}
+
+const visibilityDirect = "direct"
+
+type statusResourceType struct{}
+
+func (t statusResourceType) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Status. Posts a status and keeps it up to date by editing it, requires an access token belonging to the posting account with the `read:statuses` and `write:statuses` scopes, and `write:accounts` to pin it. Destroying the resource deletes the status.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			// inputs
+			"access_token": {
+				MarkdownDescription: "Access token of the posting account. Defaults to the provider's `access_token`, set it to post as several accounts with one provider.",
+				Optional:            true,
+				Sensitive:           true,
+				Type:                types.StringType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.RequiresReplace(),
+				},
+			},
+			"in_reply_to_id": {
+				MarkdownDescription: "identifier of the status this status replies to. Changing it posts a new status.",
+				Optional:            true,
+				Type:                types.StringType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.RequiresReplace(),
+				},
+			},
+			"language": {
+				MarkdownDescription: "Language of the status as an ISO 639 code, such as `en`. Defaults to the account's default language.",
+				Optional:            true,
+				Computed:            true,
+				Type:                types.StringType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
+			},
+			"pinned": {
+				MarkdownDescription: "Whether the status is pinned to the account's profile. Defaults to `false`.",
+				Optional:            true,
+				Computed:            true,
+				Type:                types.BoolType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					defaultValue(types.Bool{Value: false}),
+				},
+			},
+			"sensitive": {
+				MarkdownDescription: "Whether media in the status is marked as sensitive. Defaults to the account's preference, statuses with a `spoiler_text` are always sensitive.",
+				Optional:            true,
+				Computed:            true,
+				Type:                types.BoolType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
+			},
+			"spoiler_text": {
+				MarkdownDescription: "Content warning shown instead of the text until it's expanded. Defaults to none.",
+				Optional:            true,
+				Computed:            true,
+				Type:                types.StringType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					defaultValue(types.String{Value: ""}),
+				},
+			},
+			"text": {
+				MarkdownDescription: "Text of the status, as plain text",
+				Required:            true,
+				Type:                types.StringType,
+			},
+			"visibility": {
+				MarkdownDescription: "Who can see the status, one of `public`, `unlisted`, `private` or `direct`. Defaults to the account's default visibility. The visibility of a status can't be edited, so changing it posts a new status.",
+				Optional:            true,
+				Computed:            true,
+				Type:                types.StringType,
+				Validators: []tfsdk.AttributeValidator{
+					stringOneOf(privacyPublic, privacyUnlisted, privacyPrivate, visibilityDirect),
+				},
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+					resource.RequiresReplace(),
+				},
+			},
+
+			// outputs
+			"created_at": {
+				MarkdownDescription: "When the status was posted",
+				Type:                types.StringType,
+				Computed:            true,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
+			},
+			"id": {
+				MarkdownDescription: "identifier",
+				Type:                types.StringType,
+				Computed:            true,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
+			},
+			"url": {
+				MarkdownDescription: "Page of the status",
+				Type:                types.StringType,
+				Computed:            true,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
+			},
+		},
+	}, nil
+}
+
+func (t statusResourceType) NewResource(_ context.Context, in provider.Provider) (resource.Resource, diag.Diagnostics) {
+	prov, diags := convertProviderType(in)
+
+	return statusResource{
+		provider: prov,
+	}, diags
+}
+
+type statusResourceData struct {
+	AccessToken types.String `tfsdk:"access_token"`
+	InReplyToID types.String `tfsdk:"in_reply_to_id"`
+	Language    types.String `tfsdk:"language"`
+	Pinned      types.Bool   `tfsdk:"pinned"`
+	Sensitive   types.Bool   `tfsdk:"sensitive"`
+	SpoilerText types.String `tfsdk:"spoiler_text"`
+	Text        types.String `tfsdk:"text"`
+	Visibility  types.String `tfsdk:"visibility"`
+
+	CreatedAt types.String `tfsdk:"created_at"`
+	ID        types.String `tfsdk:"id"`
+	URL       types.String `tfsdk:"url"`
+}
+
+// status is a status as returned by the API. It only has the attributes the resource manages.
+type status struct {
+	ID          string    `json:"id"`
+	URL         string    `json:"url"`
+	CreatedAt   time.Time `json:"created_at"`
+	InReplyToID *string   `json:"in_reply_to_id"`
+	Language    *string   `json:"language"`
+	Pinned      bool      `json:"pinned"`
+	Sensitive   bool      `json:"sensitive"`
+	SpoilerText string    `json:"spoiler_text"`
+	Visibility  string    `json:"visibility"`
+}
+
+// statusSource is the plain text of a status, which the status itself only has as HTML.
+type statusSource struct {
+	Text        string `json:"text"`
+	SpoilerText string `json:"spoiler_text"`
+}
+
+// params returns the request parameters posting or editing the status. Visibility and the replied to
+// status can only be set when posting.
+func (d statusResourceData) params(edit bool) url.Values {
+	params := url.Values{
+		"status":       {d.Text.Value},
+		"spoiler_text": {d.SpoilerText.Value},
+	}
+
+	if !d.Language.IsNull() && !d.Language.IsUnknown() {
+		params.Set("language", d.Language.Value)
+	}
+	if !d.Sensitive.IsNull() && !d.Sensitive.IsUnknown() {
+		params.Set("sensitive", strconv.FormatBool(d.Sensitive.Value))
+	}
+
+	if edit {
+		return params
+	}
+
+	if !d.Visibility.IsNull() && !d.Visibility.IsUnknown() {
+		params.Set("visibility", d.Visibility.Value)
+	}
+	if !d.InReplyToID.IsNull() {
+		params.Set("in_reply_to_id", d.InReplyToID.Value)
+	}
+
+	return params
+}
+
+// update sets the data to the values of st, and to the text of source unless it's nil. The server strips
+// surrounding whitespace, so a text that only differs by it is kept as configured.
+func (d *statusResourceData) update(st *status, source *statusSource) {
+	if source != nil {
+		if d.Text.IsNull() || strings.TrimSpace(d.Text.Value) != source.Text {
+			d.Text = types.String{Value: source.Text}
+		}
+		if d.SpoilerText.IsNull() || strings.TrimSpace(d.SpoilerText.Value) != source.SpoilerText {
+			d.SpoilerText = types.String{Value: source.SpoilerText}
+		}
+	}
+
+	d.InReplyToID = optionalString(st.InReplyToID)
+	d.Language = optionalString(st.Language)
+	d.Pinned = types.Bool{Value: st.Pinned}
+	d.Sensitive = types.Bool{Value: st.Sensitive}
+	d.Visibility = types.String{Value: st.Visibility}
+
+	d.CreatedAt = types.String{Value: st.CreatedAt.Format(time.RFC3339)}
+	d.ID = types.String{Value: st.ID}
+	d.URL = types.String{Value: st.URL}
+}
+
+// edited returns true if the status has to be edited to change it from d to plan.
+func (d statusResourceData) edited(plan statusResourceData) bool {
+	return !d.Text.Equal(plan.Text) ||
+		!d.SpoilerText.Equal(plan.SpoilerText) ||
+		!d.Language.Equal(plan.Language) ||
+		!d.Sensitive.Equal(plan.Sensitive)
+}
+
+type statusResource struct {
+	provider mastodonProvider
+}
+
+func (r statusResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// nothing to check when destroying
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var config, plan, state statusResourceData
+
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+
+	if !req.State.Raw.IsNull() {
+		diags = req.State.Get(ctx, &state)
+		resp.Diagnostics.Append(diags...)
+	}
+
+	if resp.Diagnostics.HasError() || plan.SpoilerText.IsUnknown() {
+		return
+	}
+
+	if !config.Sensitive.IsNull() {
+		if !config.Sensitive.IsUnknown() && !config.Sensitive.Value && plan.SpoilerText.Value != "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("sensitive"),
+				"Invalid Attribute Combination",
+				"A status with a spoiler_text is always sensitive.",
+			)
+		}
+
+		return
+	}
+
+	// the server marks statuses with a content warning as sensitive, and decides when it's removed
+	switch {
+	case plan.SpoilerText.Value != "":
+		plan.Sensitive = types.Bool{Value: true}
+	case req.State.Raw.IsNull() || !plan.SpoilerText.Equal(state.SpoilerText):
+		plan.Sensitive = types.Bool{Unknown: true}
+	default:
+		return
+	}
+
+	diags = resp.Plan.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// pin pins or unpins the status with id.
+func (r statusResource) pin(ctx context.Context, client *mastodon.Client, id string, pinned bool) (*status, error) {
+	action := "/unpin"
+	if pinned {
+		action = "/pin"
+	}
+
+	var st status
+	_, err := doAPI(ctx, client, http.MethodPost, "/api/v1/statuses/"+url.PathEscape(id)+action, nil, &st)
+	if err != nil {
+		return nil, err
+	}
+
+	return &st, nil
+}
+
+func (r statusResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data statusResourceData
+
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.provider.newUserClient(ctx, data.AccessToken.Value)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var st status
+	_, err := doAPI(ctx, client, http.MethodPost, "/api/v1/statuses", data.params(false), &st)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "post status", err)
+
+		return
+	}
+
+	pinned := data.Pinned.Value
+	data.update(&st, nil)
+
+	// the status exists now, so it's kept in state even if it can't be pinned
+	if pinned {
+		pinnedStatus, err := r.pin(ctx, client, st.ID, true)
+		if err != nil {
+			addAPIError(&resp.Diagnostics, "pin status", err)
+		} else {
+			data.update(pinnedStatus, nil)
+		}
+	}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	tflog.Trace(ctx, "created a resource")
+}
+
+func (r statusResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data statusResourceData
+
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.provider.newUserClient(ctx, data.AccessToken.Value)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var st status
+	_, err := doAPI(ctx, client, http.MethodGet, "/api/v1/statuses/"+url.PathEscape(data.ID.Value), nil, &st)
+	if err != nil {
+		if isNotFound(err) {
+			resp.State.RemoveResource(ctx)
+
+			return
+		}
+
+		addAPIError(&resp.Diagnostics, "read status", err)
+
+		return
+	}
+
+	var source statusSource
+	_, err = doAPI(ctx, client, http.MethodGet, "/api/v1/statuses/"+url.PathEscape(data.ID.Value)+"/source", nil, &source)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "read status source", err)
+
+		return
+	}
+
+	data.update(&st, &source)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r statusResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data, state statusResourceData
+
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.provider.newUserClient(ctx, data.AccessToken.Value)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// editing keeps the id, so replies, boosts and favourites are kept
+	st := &status{}
+	if state.edited(data) {
+		_, err := doAPI(ctx, client, http.MethodPut, "/api/v1/statuses/"+url.PathEscape(state.ID.Value), data.params(true), st)
+		if err != nil {
+			addAPIError(&resp.Diagnostics, "edit status", err)
+
+			return
+		}
+	} else {
+		_, err := doAPI(ctx, client, http.MethodGet, "/api/v1/statuses/"+url.PathEscape(state.ID.Value), nil, st)
+		if err != nil {
+			addAPIError(&resp.Diagnostics, "read status", err)
+
+			return
+		}
+	}
+
+	// an edit that was applied is kept in state even if the status can't be pinned
+	if st.Pinned != data.Pinned.Value {
+		pinned, err := r.pin(ctx, client, state.ID.Value, data.Pinned.Value)
+		if err != nil {
+			addAPIError(&resp.Diagnostics, "pin status", err)
+		} else {
+			st = pinned
+		}
+	}
+
+	data.update(st, nil)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r statusResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data statusResourceData
+
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.provider.newUserClient(ctx, data.AccessToken.Value)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, err := doAPI(ctx, client, http.MethodDelete, "/api/v1/statuses/"+url.PathEscape(data.ID.Value), nil, nil)
+	if err != nil && !isNotFound(err) {
+		addAPIError(&resp.Diagnostics, "delete status", err)
+	}
+}
+
+func (r statusResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
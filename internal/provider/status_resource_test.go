@@ -0,0 +1,149 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/feditools/terraform-provider-mastodon/internal/fakemastodon"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccStatusResource(t *testing.T) {
+	s := fakemastodon.New()
+	defer s.Close()
+
+	account := s.AddAccount("bot")
+	app := s.AddApp("terraform", "read write")
+	token := s.AddToken(app.ClientID, account.ID, "read", "write")
+	about := s.AddStatus(account.ID, "About this bot")
+
+	var id string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccStatusResourceConfig(s, token, fmt.Sprintf(`
+	text           = <<-EOT
+		Rules:
+		1. Be nice
+	EOT
+	visibility     = "unlisted"
+	language       = "en"
+	in_reply_to_id = %q
+	pinned         = true
+`, about.ID)),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckResourceID("mastodon_status.test", &id),
+					resource.TestCheckResourceAttr("mastodon_status.test", "text", "Rules:\n1. Be nice\n"),
+					resource.TestCheckResourceAttr("mastodon_status.test", "visibility", "unlisted"),
+					resource.TestCheckResourceAttr("mastodon_status.test", "spoiler_text", ""),
+					resource.TestCheckResourceAttr("mastodon_status.test", "sensitive", "false"),
+					resource.TestCheckResourceAttr("mastodon_status.test", "pinned", "true"),
+					resource.TestCheckResourceAttrSet("mastodon_status.test", "url"),
+					func(_ *terraform.State) error {
+						st := s.Status(id)
+						if st == nil || !st.Pinned || st.Text != "Rules:\n1. Be nice" || *st.InReplyToID != about.ID {
+							return fmt.Errorf("unexpected status: %+v", st)
+						}
+
+						return nil
+					},
+				),
+			},
+			// ImportState testing, the server strips the text
+			{
+				ResourceName:            "mastodon_status.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"text"},
+			},
+			// Update and Read testing, edits keep the id
+			{
+				Config: testAccStatusResourceConfig(s, token, fmt.Sprintf(`
+	text           = "Rules: be nice"
+	spoiler_text   = "Rules"
+	visibility     = "unlisted"
+	language       = "en"
+	in_reply_to_id = %q
+`, about.ID)),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckResourceID("mastodon_status.test", &id),
+					resource.TestCheckResourceAttr("mastodon_status.test", "text", "Rules: be nice"),
+					resource.TestCheckResourceAttr("mastodon_status.test", "sensitive", "true"),
+					resource.TestCheckResourceAttr("mastodon_status.test", "pinned", "false"),
+					func(_ *terraform.State) error {
+						if st := s.Status(id); st.EditedAt == nil || st.Pinned {
+							return fmt.Errorf("expected status to be edited and unpinned: %+v", st)
+						}
+
+						return nil
+					},
+				),
+			},
+			// Drift testing, status edited outside of terraform
+			{
+				PreConfig: func() {
+					s.UpdateStatus(id, func(st *fakemastodon.Status) {
+						st.Text = "Edited"
+					})
+				},
+				Config: testAccStatusResourceConfig(s, token, fmt.Sprintf(`
+	text           = "Rules: be nice"
+	spoiler_text   = "Rules"
+	visibility     = "unlisted"
+	language       = "en"
+	in_reply_to_id = %q
+`, about.ID)),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckResourceID("mastodon_status.test", &id),
+					func(_ *terraform.State) error {
+						if st := s.Status(id); st.Text != "Rules: be nice" {
+							return fmt.Errorf("expected text to be restored, got: %s", st.Text)
+						}
+
+						return nil
+					},
+				),
+			},
+			// A spoiler always makes the status sensitive
+			{
+				Config: testAccStatusResourceConfig(s, token, `
+	text         = "Rules: be nice"
+	spoiler_text = "Rules"
+	sensitive    = false
+`),
+				ExpectError: regexp.MustCompile("A status with a spoiler_text is always sensitive"),
+			},
+			// Direct statuses can't be pinned
+			{
+				Config: testAccStatusResourceConfig(s, token, `
+	text       = "@admin hello"
+	visibility = "direct"
+	pinned     = true
+`),
+				ExpectError: regexp.MustCompile("can't be pinned"),
+			},
+		},
+		CheckDestroy: func(_ *terraform.State) error {
+			if st := s.Status(id); st != nil {
+				return fmt.Errorf("status not deleted: %s", id)
+			}
+
+			return nil
+		},
+	})
+}
+
+const testAccStatusResourceConfigTmpl = `
+resource "mastodon_status" "test" {
+%[1]s}
+`
+
+func testAccStatusResourceConfig(s *fakemastodon.Server, token, attributes string) string {
+	return testAccProviderConfigWithToken(s, token) + fmt.Sprintf(testAccStatusResourceConfigTmpl, attributes)
+}
@@ -0,0 +1,101 @@
+package provider
+
+import (
+	"sync"
+)
+
+// tokenCacheKey identifies an application token by the credentials and scopes it was issued for.
+type tokenCacheKey struct {
+	clientID string
+	scopes   string
+}
+
+// tokenCacheEntry holds the token for a single application. Its lock is held while authenticating
+// so parallel callers for the same application wait for one token request instead of issuing their own.
+type tokenCacheEntry struct {
+	sync.Mutex
+
+	token string
+}
+
+// tokenCache caches application access tokens keyed by client id and scopes.
+type tokenCache struct {
+	lock    sync.Mutex
+	entries map[tokenCacheKey]*tokenCacheEntry
+}
+
+func newTokenCache() *tokenCache {
+	return &tokenCache{
+		entries: map[tokenCacheKey]*tokenCacheEntry{},
+	}
+}
+
+func (c *tokenCache) entry(key tokenCacheKey) *tokenCacheEntry {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		e = &tokenCacheEntry{}
+		c.entries[key] = e
+	}
+
+	return e
+}
+
+// get returns the cached token for key, calling fetch to obtain one if none is cached.
+func (c *tokenCache) get(key tokenCacheKey, fetch func() (string, error)) (string, error) {
+	e := c.entry(key)
+
+	e.Lock()
+	defer e.Unlock()
+
+	if e.token != "" {
+		return e.token, nil
+	}
+
+	token, err := fetch()
+	if err != nil {
+		return "", err
+	}
+	e.token = token
+
+	return token, nil
+}
+
+// evict removes token from the cache. A token that has already been replaced is left alone
+// so a stale caller can't evict a freshly issued token.
+func (c *tokenCache) evict(key tokenCacheKey, token string) {
+	e := c.entry(key)
+
+	e.Lock()
+	defer e.Unlock()
+
+	if e.token == token {
+		e.token = ""
+	}
+}
+
+// take removes and returns every token cached for clientID.
+func (c *tokenCache) take(clientID string) []string {
+	c.lock.Lock()
+	entries := make([]*tokenCacheEntry, 0)
+	for key, e := range c.entries {
+		if key.clientID == clientID {
+			entries = append(entries, e)
+		}
+	}
+	c.lock.Unlock()
+
+	var tokens []string
+	for _, e := range entries {
+		e.Lock()
+		if e.token != "" {
+			tokens = append(tokens, e.token)
+			e.token = ""
+		}
+		e.Unlock()
+	}
+
+	return tokens
+}
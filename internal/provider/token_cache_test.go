@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestTokenCache_Get(t *testing.T) {
+	cache := newTokenCache()
+	key := tokenCacheKey{clientID: "client", scopes: "read"}
+
+	var fetches int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token, err := cache.get(key, func() (string, error) {
+				atomic.AddInt32(&fetches, 1)
+				return "token", nil
+			})
+			if err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+			if token != "token" {
+				t.Errorf("unexpected token, got: %s, want: %s", token, "token")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if fetches != 1 {
+		t.Errorf("unexpected fetch count, got: %d, want: %d", fetches, 1)
+	}
+}
+
+func TestTokenCache_Keys(t *testing.T) {
+	cache := newTokenCache()
+
+	tokenA, _ := cache.get(tokenCacheKey{clientID: "a", scopes: "read"}, func() (string, error) { return "token-a", nil })
+	tokenB, _ := cache.get(tokenCacheKey{clientID: "b", scopes: "read"}, func() (string, error) { return "token-b", nil })
+	tokenAdmin, _ := cache.get(tokenCacheKey{clientID: "a", scopes: "admin:read"}, func() (string, error) { return "token-admin", nil })
+
+	if tokenA != "token-a" || tokenB != "token-b" || tokenAdmin != "token-admin" {
+		t.Errorf("tokens shared between keys, got: %s, %s, %s", tokenA, tokenB, tokenAdmin)
+	}
+}
+
+func TestTokenCache_Evict(t *testing.T) {
+	cache := newTokenCache()
+	key := tokenCacheKey{clientID: "client", scopes: "read"}
+
+	_, _ = cache.get(key, func() (string, error) { return "old", nil })
+	cache.evict(key, "old")
+	_, _ = cache.get(key, func() (string, error) { return "new", nil })
+
+	// evicting a stale token must not drop the new one
+	cache.evict(key, "old")
+	token, _ := cache.get(key, func() (string, error) { return "unexpected", nil })
+	if token != "new" {
+		t.Errorf("unexpected token, got: %s, want: %s", token, "new")
+	}
+}
+
+func TestTokenCache_Take(t *testing.T) {
+	cache := newTokenCache()
+
+	_, _ = cache.get(tokenCacheKey{clientID: "a", scopes: "read"}, func() (string, error) { return "token-read", nil })
+	_, _ = cache.get(tokenCacheKey{clientID: "a", scopes: "write"}, func() (string, error) { return "token-write", nil })
+	_, _ = cache.get(tokenCacheKey{clientID: "b", scopes: "read"}, func() (string, error) { return "token-b", nil })
+
+	tokens := cache.take("a")
+	sort.Strings(tokens)
+	if len(tokens) != 2 || tokens[0] != "token-read" || tokens[1] != "token-write" {
+		t.Errorf("unexpected tokens, got: %v", tokens)
+	}
+	if tokens := cache.take("a"); len(tokens) != 0 {
+		t.Errorf("expected tokens to be removed, got: %v", tokens)
+	}
+	if tokens := cache.take("b"); len(tokens) != 1 {
+		t.Errorf("expected other client's token to remain, got: %v", tokens)
+	}
+}
@@ -0,0 +1,170 @@
+package provider
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// transportConfig holds the settings used to build the provider's transport.
+type transportConfig struct {
+	CACertFile         string
+	CACertPEM          string
+	ClientCertPEM      string
+	ClientKeyPEM       string
+	InsecureSkipVerify bool
+	ProxyURL           string
+	RequestTimeout     time.Duration
+
+	MaxRetries   int
+	MaxRetryWait time.Duration
+}
+
+// newTransport builds the transport shared by every client the provider creates.
+func newTransport(config transportConfig) (http.RoundTripper, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: config.InsecureSkipVerify,
+	}
+
+	// custom certificate authorities
+	if config.CACertFile != "" || config.CACertPEM != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		if config.CACertFile != "" {
+			pem, err := os.ReadFile(config.CACertFile)
+			if err != nil {
+				diags.AddAttributeError(
+					path.Root("ca_cert_file"),
+					"Invalid CA Certificate File",
+					fmt.Sprintf("Unable to read CA certificate file, got error: %s", err),
+				)
+			} else if !pool.AppendCertsFromPEM(pem) {
+				diags.AddAttributeError(
+					path.Root("ca_cert_file"),
+					"Invalid CA Certificate File",
+					fmt.Sprintf("No PEM encoded certificates found in %s.", config.CACertFile),
+				)
+			}
+		}
+
+		if config.CACertPEM != "" && !pool.AppendCertsFromPEM([]byte(config.CACertPEM)) {
+			diags.AddAttributeError(
+				path.Root("ca_cert_pem"),
+				"Invalid CA Certificate",
+				"No PEM encoded certificates found in ca_cert_pem.",
+			)
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	// client certificate
+	switch {
+	case config.ClientCertPEM != "" && config.ClientKeyPEM != "":
+		cert, err := tls.X509KeyPair([]byte(config.ClientCertPEM), []byte(config.ClientKeyPEM))
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("client_cert_pem"),
+				"Invalid Client Certificate",
+				fmt.Sprintf("Unable to load client certificate and key, got error: %s", err),
+			)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	case config.ClientCertPEM != "":
+		diags.AddAttributeError(
+			path.Root("client_key_pem"),
+			"Missing Client Key",
+			"client_key_pem must be set when client_cert_pem is set.",
+		)
+	case config.ClientKeyPEM != "":
+		diags.AddAttributeError(
+			path.Root("client_cert_pem"),
+			"Missing Client Certificate",
+			"client_cert_pem must be set when client_key_pem is set.",
+		)
+	}
+
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	base.TLSClientConfig = tlsConfig
+
+	// proxy
+	if config.ProxyURL != "" {
+		proxyURL, err := url.Parse(config.ProxyURL)
+		if err != nil || proxyURL.Scheme == "" || proxyURL.Host == "" {
+			diags.AddAttributeError(
+				path.Root("proxy_url"),
+				"Invalid Proxy URL",
+				fmt.Sprintf("proxy_url must be an absolute URL such as http://proxy.example:3128, got %q.", config.ProxyURL),
+			)
+		} else {
+			base.Proxy = http.ProxyURL(proxyURL)
+		}
+	}
+
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	var transport http.RoundTripper = &loggingTransport{
+		base: base,
+	}
+	if config.RequestTimeout > 0 {
+		transport = &timeoutTransport{
+			base:    transport,
+			timeout: config.RequestTimeout,
+		}
+	}
+
+	return newRateLimitTransport(transport, config.MaxRetries, config.MaxRetryWait), diags
+}
+
+// timeoutTransport limits how long a single attempt at a request may take, including reading
+// the response body. Waiting on the rate limit between attempts isn't counted.
+type timeoutTransport struct {
+	base    http.RoundTripper
+	timeout time.Duration
+}
+
+func (t *timeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+
+		return nil, err
+	}
+
+	resp.Body = &cancelOnCloseBody{
+		ReadCloser: resp.Body,
+		cancel:     cancel,
+	}
+
+	return resp, nil
+}
+
+// cancelOnCloseBody releases a request's context once its body has been closed.
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+
+	return err
+}
@@ -0,0 +1,99 @@
+package provider
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNewTransport_TLS(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	caPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw}))
+
+	tables := []struct {
+		name    string
+		config  transportConfig
+		wantErr bool
+	}{
+		{"default", transportConfig{}, true},
+		{"ca_cert_pem", transportConfig{CACertPEM: caPEM}, false},
+		{"insecure_skip_verify", transportConfig{InsecureSkipVerify: true}, false},
+	}
+
+	for _, table := range tables {
+		table := table
+
+		t.Run(table.name, func(t *testing.T) {
+			transport, diags := newTransport(table.config)
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+
+			client := http.Client{Transport: transport}
+			resp, err := client.Get(ts.URL)
+			if table.wantErr {
+				if err == nil {
+					resp.Body.Close()
+					t.Errorf("expected certificate error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			resp.Body.Close()
+		})
+	}
+}
+
+func TestNewTransport_Invalid(t *testing.T) {
+	tables := []struct {
+		name   string
+		config transportConfig
+	}{
+		{"ca_cert_file", transportConfig{CACertFile: "testdata/missing.pem"}},
+		{"ca_cert_pem", transportConfig{CACertPEM: "not a certificate"}},
+		{"client_cert_pem", transportConfig{ClientCertPEM: "not a certificate"}},
+		{"client_key_pem", transportConfig{ClientKeyPEM: "not a key"}},
+		{"proxy_url", transportConfig{ProxyURL: "proxy.example"}},
+	}
+
+	for _, table := range tables {
+		table := table
+
+		t.Run(table.name, func(t *testing.T) {
+			_, diags := newTransport(table.config)
+			if !diags.HasError() {
+				t.Errorf("expected diagnostics, got none")
+			}
+		})
+	}
+}
+
+func TestNewTransport_RequestTimeout(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer ts.Close()
+
+	transport, diags := newTransport(transportConfig{RequestTimeout: 50 * time.Millisecond})
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	client := http.Client{Transport: transport}
+	resp, err := client.Get(ts.URL)
+	if err == nil {
+		resp.Body.Close()
+		t.Errorf("expected timeout error, got none")
+	}
+}
@@ -0,0 +1,100 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"net/netip"
+	"strings"
+)
+
+// stringOneOf returns a validator which ensures a string attribute is one of values.
+func stringOneOf(values ...string) tfsdk.AttributeValidator {
+	return stringOneOfValidator{
+		values: values,
+	}
+}
+
+type stringOneOfValidator struct {
+	values []string
+}
+
+func (v stringOneOfValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("value must be one of: %s", strings.Join(v.values, ", "))
+}
+
+func (v stringOneOfValidator) MarkdownDescription(_ context.Context) string {
+	return fmt.Sprintf("value must be one of: `%s`", strings.Join(v.values, "`, `"))
+}
+
+func (v stringOneOfValidator) Validate(ctx context.Context, req tfsdk.ValidateAttributeRequest, resp *tfsdk.ValidateAttributeResponse) {
+	var s types.String
+	diags := tfsdk.ValueAs(ctx, req.AttributeConfig, &s)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() || s.IsNull() || s.IsUnknown() {
+		return
+	}
+
+	for _, value := range v.values {
+		if s.Value == value {
+			return
+		}
+	}
+
+	resp.Diagnostics.AddAttributeError(
+		req.AttributePath,
+		"Invalid Attribute Value",
+		fmt.Sprintf("Attribute %s %s, got: %q", req.AttributePath, v.Description(ctx), s.Value),
+	)
+}
+
+// ipOrCIDR returns a validator which ensures a string attribute is an IP address or CIDR range.
+func ipOrCIDR() tfsdk.AttributeValidator {
+	return ipOrCIDRValidator{}
+}
+
+type ipOrCIDRValidator struct{}
+
+func (v ipOrCIDRValidator) Description(_ context.Context) string {
+	return "value must be an IP address or CIDR range"
+}
+
+func (v ipOrCIDRValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v ipOrCIDRValidator) Validate(ctx context.Context, req tfsdk.ValidateAttributeRequest, resp *tfsdk.ValidateAttributeResponse) {
+	var s types.String
+	diags := tfsdk.ValueAs(ctx, req.AttributeConfig, &s)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() || s.IsNull() || s.IsUnknown() {
+		return
+	}
+
+	if _, ok := parseIPOrCIDR(s.Value); ok {
+		return
+	}
+
+	resp.Diagnostics.AddAttributeError(
+		req.AttributePath,
+		"Invalid Attribute Value",
+		fmt.Sprintf("Attribute %s %s, got: %q", req.AttributePath, v.Description(ctx), s.Value),
+	)
+}
+
+// parseIPOrCIDR parses an IP address or CIDR range, treating addresses as single address ranges.
+func parseIPOrCIDR(s string) (netip.Prefix, bool) {
+	if prefix, err := netip.ParsePrefix(s); err == nil {
+		return prefix, true
+	}
+
+	addr, err := netip.ParseAddr(s)
+	if err != nil || addr.Zone() != "" {
+		return netip.Prefix{}, false
+	}
+
+	return netip.PrefixFrom(addr, addr.BitLen()), true
+}
@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestStringOneOf(t *testing.T) {
+	tables := []struct {
+		value   attr.Value
+		isError bool
+	}{
+		{types.String{Value: "silence"}, false},
+		{types.String{Value: "suspend"}, false},
+		{types.String{Value: "Suspend"}, true},
+		{types.String{Value: ""}, true},
+		{types.String{Null: true}, false},
+		{types.String{Unknown: true}, false},
+	}
+
+	for i, table := range tables {
+		req := tfsdk.ValidateAttributeRequest{
+			AttributePath:   path.Root("test"),
+			AttributeConfig: table.value,
+		}
+		var resp tfsdk.ValidateAttributeResponse
+
+		stringOneOf("noop", "silence", "suspend").Validate(context.Background(), req, &resp)
+		if resp.Diagnostics.HasError() != table.isError {
+			t.Errorf("[%d] unexpected validation result for %s, got error: %t, want: %t", i, table.value, resp.Diagnostics.HasError(), table.isError)
+		}
+	}
+}
+
+func TestIPOrCIDR(t *testing.T) {
+	tables := []struct {
+		value   attr.Value
+		isError bool
+	}{
+		{types.String{Value: "192.0.2.1"}, false},
+		{types.String{Value: "192.0.2.0/24"}, false},
+		{types.String{Value: "2001:db8::/32"}, false},
+		{types.String{Value: "2001:db8::1"}, false},
+		{types.String{Value: "192.0.2.0/33"}, true},
+		{types.String{Value: "192.0.2"}, true},
+		{types.String{Value: "example.com"}, true},
+		{types.String{Value: "fe80::1%eth0"}, true},
+		{types.String{Null: true}, false},
+		{types.String{Unknown: true}, false},
+	}
+
+	for i, table := range tables {
+		req := tfsdk.ValidateAttributeRequest{
+			AttributePath:   path.Root("test"),
+			AttributeConfig: table.value,
+		}
+		var resp tfsdk.ValidateAttributeResponse
+
+		ipOrCIDR().Validate(context.Background(), req, &resp)
+		if resp.Diagnostics.HasError() != table.isError {
+			t.Errorf("[%d] unexpected validation result for %s, got error: %t, want: %t", i, table.value, resp.Diagnostics.HasError(), table.isError)
+		}
+	}
+}